@@ -0,0 +1,20 @@
+package engi
+
+// BodyAs - returns request body requested by 'parameter.BodyOf[T]' (or deprecated 'parameter.Body')
+// and false if body wasn't requested or has another type.
+//
+//	note, ok := engi.BodyAs[entity.NotesRequest](request)
+func BodyAs[T any](request Request) (T, bool) {
+	switch body := request.Body().(type) {
+	case *T:
+		if body != nil {
+			return *body, true
+		}
+	case T:
+		return body, true
+	}
+
+	var empty T
+
+	return empty, false
+}
@@ -0,0 +1,135 @@
+package engi_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/KlyuchnikovV/engi"
+	"github.com/KlyuchnikovV/engi/parameter"
+)
+
+type note struct {
+	Title string `json:"title" xml:"title"`
+}
+
+type bodyAPI struct {
+	template *note
+}
+
+func (api bodyAPI) Prefix() string { return "b" }
+
+func (api bodyAPI) Routers() engi.Routes {
+	var echo = func(_ context.Context, request engi.Request, response engi.Response) error {
+		body, ok := engi.BodyAs[note](request)
+		if !ok {
+			return response.Error(http.StatusTeapot, "body has another type")
+		}
+
+		return response.OK(body.Title)
+	}
+
+	return engi.Routes{
+		"of":         engi.POST(echo, parameter.BodyOf[note]()),
+		"custom":     engi.POST(echo, parameter.CustomBodyOf[note](json.Unmarshal)),
+		"deprecated": engi.POST(echo, parameter.Body(api.template)),
+		"other": engi.POST(func(_ context.Context, request engi.Request, response engi.Response) error {
+			if _, ok := engi.BodyAs[string](request); !ok {
+				return response.Error(http.StatusTeapot, "body has another type")
+			}
+
+			return response.OK("string")
+		}, parameter.BodyOf[note]()),
+	}
+}
+
+// post - serves POST request with body of content type.
+func post(e *engi.Engine, target, contentType, body string) *httptest.ResponseRecorder {
+	var (
+		recorder = httptest.NewRecorder()
+		request  = httptest.NewRequest(http.MethodPost, target, strings.NewReader(body))
+	)
+
+	request.Header.Set("Content-Type", contentType)
+
+	e.ServeHTTP(recorder, request)
+
+	return recorder
+}
+
+func TestBodyBinding(t *testing.T) {
+	var (
+		template = &note{Title: "template"}
+		e        = engi.New(":0")
+	)
+
+	if err := e.RegisterServices(bodyAPI{template: template}); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, test := range []struct {
+		name        string
+		target      string
+		contentType string
+		body        string
+		code        int
+		expected    string
+	}{
+		{"json", "/b/of", "application/json", `{"title":"a"}`, http.StatusOK, `"a"`},
+		{"xml", "/b/of", "application/xml", `<note><title>b</title></note>`, http.StatusOK, `"b"`},
+		{"custom unmarshaler", "/b/custom", "text/csv", `{"title":"c"}`, http.StatusOK, `"c"`},
+		{"deprecated pointer", "/b/deprecated", "application/json", `{"title":"d"}`, http.StatusOK, `"d"`},
+		{"absent field", "/b/deprecated", "application/json", `{}`, http.StatusOK, `""`},
+		{"another type", "/b/other", "application/json", `{"title":"e"}`, http.StatusTeapot, ""},
+		{"invalid body", "/b/of", "application/json", `{"title":`, http.StatusBadRequest, ""},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			var recorder = post(e, test.target, test.contentType, test.body)
+
+			if recorder.Code != test.code {
+				t.Fatalf("expected %d, got %d (%s)", test.code, recorder.Code, recorder.Body)
+			}
+
+			if test.expected != "" && recorder.Body.String() != test.expected {
+				t.Fatalf("expected '%s', got '%s'", test.expected, recorder.Body)
+			}
+		})
+	}
+
+	if template.Title != "template" {
+		t.Fatalf("expected template untouched, got '%s'", template.Title)
+	}
+}
+
+func TestBodyIsNotSharedBetweenRequests(t *testing.T) {
+	var e = engi.New(":0")
+
+	if err := e.RegisterServices(bodyAPI{template: new(note)}); err != nil {
+		t.Fatal(err)
+	}
+
+	var wg sync.WaitGroup
+
+	for _, target := range []string{"/b/of", "/b/deprecated"} {
+		for i := 0; i < 20; i++ {
+			wg.Add(1)
+
+			go func(target, title string) {
+				defer wg.Done()
+
+				var recorder = post(e, target, "application/json", fmt.Sprintf(`{"title":"%s"}`, title))
+
+				if expected := `"` + title + `"`; recorder.Body.String() != expected {
+					t.Errorf("%s: expected '%s', got '%s'", target, expected, recorder.Body)
+				}
+			}(target, fmt.Sprint(i))
+		}
+	}
+
+	wg.Wait()
+}
@@ -0,0 +1,422 @@
+package engi
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/KlyuchnikovV/engi/internal/types"
+	"github.com/KlyuchnikovV/engi/response"
+)
+
+const envPrefix = "ENGI_"
+
+var ErrInvalidConfig = fmt.Errorf("invalid configuration")
+
+type (
+	// Duration - time.Duration parsed from strings like "5s" or "1m30s".
+	Duration time.Duration
+
+	config struct {
+		Address         *string   `json:"address"          yaml:"address"`
+		Prefix          *string   `json:"prefix"           yaml:"prefix"`
+		ShutdownTimeout *Duration `json:"shutdown_timeout" yaml:"shutdown_timeout"`
+
+		Timeouts struct {
+			Read       *Duration `json:"read"        yaml:"read"`
+			Write      *Duration `json:"write"       yaml:"write"`
+			Idle       *Duration `json:"idle"        yaml:"idle"`
+			ReadHeader *Duration `json:"read_header" yaml:"read_header"`
+		} `json:"timeouts" yaml:"timeouts"`
+
+		Log struct {
+			Level  string `json:"level"  yaml:"level"`
+			Format string `json:"format" yaml:"format"`
+		} `json:"log" yaml:"log"`
+
+		Response struct {
+			Format  string `json:"format"  yaml:"format"`
+			Wrapper string `json:"wrapper" yaml:"wrapper"`
+		} `json:"response" yaml:"response"`
+
+		CORS struct {
+			Origins []string `json:"origins" yaml:"origins"`
+			Methods []string `json:"methods" yaml:"methods"`
+			Headers []string `json:"headers" yaml:"headers"`
+		} `json:"cors" yaml:"cors"`
+
+		Auth struct {
+			Type     string `json:"type"     yaml:"type"`
+			Username string `json:"username" yaml:"username"`
+			Password string `json:"password" yaml:"password"`
+			Token    string `json:"token"    yaml:"token"`
+			Key      string `json:"key"      yaml:"key"`
+			Value    string `json:"value"    yaml:"value"`
+			In       string `json:"in"       yaml:"in"`
+		} `json:"auth" yaml:"auth"`
+	}
+)
+
+func (d *Duration) UnmarshalText(text []byte) error {
+	duration, err := time.ParseDuration(string(text))
+	if err != nil {
+		return err
+	}
+
+	*d = Duration(duration)
+
+	return nil
+}
+
+// FromConfig - reads engine configuration from JSON or YAML file (chosen by extension) and
+// 'ENGI_*' environment variables, environment overrides file. If file is empty only environment is used.
+//
+// Supported keys (environment variable in brackets):
+//   - address (ENGI_ADDRESS), prefix (ENGI_PREFIX), shutdown_timeout (ENGI_SHUTDOWN_TIMEOUT);
+//   - timeouts.read, timeouts.write, timeouts.idle, timeouts.read_header (ENGI_TIMEOUTS_READ...) - applied to servers
+//     of all listeners, listener options (e.g. ReadTimeout) override them;
+//   - log.level - debug, info, warn or error; log.format - text or json (ENGI_LOG_LEVEL, ENGI_LOG_FORMAT);
+//   - response.format - json or xml; response.wrapper - as_is or object (ENGI_RESPONSE_FORMAT, ENGI_RESPONSE_WRAPPER);
+//   - cors.origins, cors.methods, cors.headers - comma-separated in environment (ENGI_CORS_ORIGINS...);
+//   - auth.type - none, basic, bearer or api_key with auth.username, auth.password, auth.token,
+//     auth.key, auth.value and auth.in - query, header or cookie (ENGI_AUTH_TYPE...).
+//
+// Unknown keys and invalid values are reported all at once.
+func FromConfig(file string) ([]Option, error) {
+	var cfg config
+
+	if file != "" {
+		if err := cfg.readFile(file); err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrInvalidConfig, err)
+		}
+	}
+
+	var envErr = cfg.readEnv(os.Environ())
+
+	options, err := cfg.options()
+	if err = errors.Join(envErr, err); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidConfig, err)
+	}
+
+	return options, nil
+}
+
+func (cfg *config) readFile(file string) error {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return err
+	}
+
+	switch strings.ToLower(filepath.Ext(file)) {
+	case ".json":
+		var decoder = json.NewDecoder(bytes.NewReader(data))
+		decoder.DisallowUnknownFields()
+
+		err = decoder.Decode(cfg)
+	case ".yaml", ".yml":
+		var decoder = yaml.NewDecoder(bytes.NewReader(data))
+		decoder.KnownFields(true)
+
+		err = decoder.Decode(cfg)
+	default:
+		return fmt.Errorf("unsupported config file format: '%s'", file)
+	}
+
+	if err != nil {
+		return fmt.Errorf("reading '%s' failed: %w", file, err)
+	}
+
+	return nil
+}
+
+func (cfg *config) readEnv(environ []string) error {
+	var (
+		errs   = make([]error, 0)
+		fields = cfg.envFields()
+	)
+
+	for _, env := range environ {
+		key, value, _ := strings.Cut(env, "=")
+		if !strings.HasPrefix(key, envPrefix) {
+			continue
+		}
+
+		set, ok := fields[strings.TrimPrefix(key, envPrefix)]
+		if !ok {
+			errs = append(errs, fmt.Errorf("unknown environment variable '%s'", key))
+			continue
+		}
+
+		if err := set(value); err != nil {
+			errs = append(errs, fmt.Errorf("environment variable '%s': %w", key, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func (cfg *config) envFields() map[string]func(string) error {
+	var (
+		str = func(field *string) func(string) error {
+			return func(value string) error {
+				*field = value
+				return nil
+			}
+		}
+		optional = func(field **string) func(string) error {
+			return func(value string) error {
+				*field = &value
+				return nil
+			}
+		}
+		list = func(field *[]string) func(string) error {
+			return func(value string) error {
+				*field = splitList(value)
+				return nil
+			}
+		}
+		duration = func(field **Duration) func(string) error {
+			return func(value string) error {
+				*field = new(Duration)
+				return (*field).UnmarshalText([]byte(value))
+			}
+		}
+	)
+
+	return map[string]func(string) error{
+		"ADDRESS":              optional(&cfg.Address),
+		"PREFIX":               optional(&cfg.Prefix),
+		"SHUTDOWN_TIMEOUT":     duration(&cfg.ShutdownTimeout),
+		"TIMEOUTS_READ":        duration(&cfg.Timeouts.Read),
+		"TIMEOUTS_WRITE":       duration(&cfg.Timeouts.Write),
+		"TIMEOUTS_IDLE":        duration(&cfg.Timeouts.Idle),
+		"TIMEOUTS_READ_HEADER": duration(&cfg.Timeouts.ReadHeader),
+		"LOG_LEVEL":            str(&cfg.Log.Level),
+		"LOG_FORMAT":           str(&cfg.Log.Format),
+		"RESPONSE_FORMAT":      str(&cfg.Response.Format),
+		"RESPONSE_WRAPPER":     str(&cfg.Response.Wrapper),
+		"CORS_ORIGINS":         list(&cfg.CORS.Origins),
+		"CORS_METHODS":         list(&cfg.CORS.Methods),
+		"CORS_HEADERS":         list(&cfg.CORS.Headers),
+		"AUTH_TYPE":            str(&cfg.Auth.Type),
+		"AUTH_USERNAME":        str(&cfg.Auth.Username),
+		"AUTH_PASSWORD":        str(&cfg.Auth.Password),
+		"AUTH_TOKEN":           str(&cfg.Auth.Token),
+		"AUTH_KEY":             str(&cfg.Auth.Key),
+		"AUTH_VALUE":           str(&cfg.Auth.Value),
+		"AUTH_IN":              str(&cfg.Auth.In),
+	}
+}
+
+// options - validates configuration and converts it to engine options.
+func (cfg *config) options() ([]Option, error) {
+	var (
+		options = make([]Option, 0)
+		errs    = make([]error, 0)
+		add     = func(option Option, err error) {
+			if err != nil {
+				errs = append(errs, err)
+			} else if option != nil {
+				options = append(options, option)
+			}
+		}
+	)
+
+	if cfg.Address != nil {
+		options = append(options, WithAddress(*cfg.Address))
+	}
+
+	if cfg.Prefix != nil {
+		options = append(options, WithPrefix(*cfg.Prefix))
+	}
+
+	if cfg.ShutdownTimeout != nil {
+		options = append(options, WithShutdownTimeout(time.Duration(*cfg.ShutdownTimeout)))
+	}
+
+	add(cfg.timeoutsOption())
+	add(cfg.logOption())
+	add(cfg.responseOption())
+	add(cfg.corsOption())
+	add(cfg.authOption())
+
+	return options, errors.Join(errs...)
+}
+
+func (cfg *config) timeoutsOption() (Option, error) {
+	var timeouts = []struct {
+		name  string
+		value *Duration
+		set   func(*http.Server, time.Duration)
+	}{
+		{"read", cfg.Timeouts.Read, func(s *http.Server, d time.Duration) { s.ReadTimeout = d }},
+		{"write", cfg.Timeouts.Write, func(s *http.Server, d time.Duration) { s.WriteTimeout = d }},
+		{"idle", cfg.Timeouts.Idle, func(s *http.Server, d time.Duration) { s.IdleTimeout = d }},
+		{"read_header", cfg.Timeouts.ReadHeader, func(s *http.Server, d time.Duration) { s.ReadHeaderTimeout = d }},
+	}
+
+	var isSet bool
+
+	for _, timeout := range timeouts {
+		if timeout.value == nil {
+			continue
+		}
+
+		if *timeout.value < 0 {
+			return nil, fmt.Errorf("timeouts.%s can't be negative", timeout.name)
+		}
+
+		isSet = true
+	}
+
+	if !isSet {
+		return nil, nil
+	}
+
+	var set = func(server *http.Server) {
+		for _, timeout := range timeouts {
+			if timeout.value != nil {
+				timeout.set(server, time.Duration(*timeout.value))
+			}
+		}
+	}
+
+	return func(engine *Engine) {
+		set(engine.server)
+
+		engine.serverTimeouts = append(engine.serverTimeouts, set)
+	}, nil
+}
+
+func (cfg *config) logOption() (Option, error) {
+	if cfg.Log.Level == "" && cfg.Log.Format == "" {
+		return nil, nil
+	}
+
+	var level slog.Level
+	if cfg.Log.Level != "" {
+		if err := level.UnmarshalText([]byte(cfg.Log.Level)); err != nil {
+			return nil, fmt.Errorf("log.level: %w", err)
+		}
+	}
+
+	var options = &slog.HandlerOptions{Level: level}
+
+	switch strings.ToLower(cfg.Log.Format) {
+	case "", "text":
+		return WithLogger(slog.NewTextHandler(os.Stdout, options)), nil
+	case "json":
+		return WithLogger(slog.NewJSONHandler(os.Stdout, options)), nil
+	default:
+		return nil, fmt.Errorf("log.format: unknown format '%s'", cfg.Log.Format)
+	}
+}
+
+func (cfg *config) responseOption() (Option, error) {
+	if cfg.Response.Format == "" && cfg.Response.Wrapper == "" {
+		return nil, nil
+	}
+
+	var object types.Responser
+
+	switch strings.ToLower(cfg.Response.Wrapper) {
+	case "", "as_is":
+		object = new(response.AsIs)
+	case "object":
+		object = new(response.AsObject)
+	default:
+		return nil, fmt.Errorf("response.wrapper: unknown wrapper '%s'", cfg.Response.Wrapper)
+	}
+
+	switch strings.ToLower(cfg.Response.Format) {
+	case "", "json":
+		return ResponseAsJSON(object), nil
+	case "xml":
+		return ResponseAsXML(object), nil
+	default:
+		return nil, fmt.Errorf("response.format: unknown format '%s'", cfg.Response.Format)
+	}
+}
+
+func (cfg *config) corsOption() (Option, error) {
+	if len(cfg.CORS.Origins) == 0 && len(cfg.CORS.Methods) == 0 && len(cfg.CORS.Headers) == 0 {
+		return nil, nil
+	}
+
+	if len(cfg.CORS.Origins) == 0 {
+		return nil, fmt.Errorf("cors.origins: should be set if cors is configured")
+	}
+
+	return WithMiddlewares(UseCORS(
+		AllowedOrigins(cfg.CORS.Origins...),
+		AllowedMethods(cfg.CORS.Methods...),
+		AllowedHeaders(cfg.CORS.Headers...),
+	)), nil
+}
+
+func (cfg *config) authOption() (Option, error) {
+	var auth = cfg.Auth
+
+	switch strings.ToLower(auth.Type) {
+	case "":
+		return nil, nil
+	case "none":
+		return WithMiddlewares(UseAuthorization(NoAuth)), nil
+	case "basic":
+		if auth.Username == "" || auth.Password == "" {
+			return nil, fmt.Errorf("auth: 'username' and 'password' are required for basic auth")
+		}
+
+		return WithMiddlewares(UseAuthorization(BasicAuth(auth.Username, auth.Password))), nil
+	case "bearer":
+		if auth.Token == "" {
+			return nil, fmt.Errorf("auth: 'token' is required for bearer auth")
+		}
+
+		return WithMiddlewares(UseAuthorization(BearerAuth(func(token string) bool {
+			return token == auth.Token
+		}))), nil
+	case "api_key":
+		if auth.Key == "" || auth.Value == "" {
+			return nil, fmt.Errorf("auth: 'key' and 'value' are required for api_key auth")
+		}
+
+		var place AuthKeyPlacing
+
+		switch strings.ToLower(auth.In) {
+		case "", "header":
+			place = InHeader
+		case "query":
+			place = InQuery
+		case "cookie":
+			place = InCookie
+		default:
+			return nil, fmt.Errorf("auth.in: unknown placing '%s'", auth.In)
+		}
+
+		return WithMiddlewares(UseAuthorization(APIKeyAuth(auth.Key, auth.Value, place))), nil
+	default:
+		return nil, fmt.Errorf("auth.type: unknown type '%s'", auth.Type)
+	}
+}
+
+func splitList(value string) []string {
+	var result = make([]string, 0)
+
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			result = append(result, item)
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,199 @@
+package engi_test
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/KlyuchnikovV/engi"
+)
+
+func TestFromConfig(t *testing.T) {
+	for _, test := range []struct {
+		name    string
+		file    string
+		content string
+		env     map[string]string
+		errs    []string
+		target  string
+		code    int
+	}{
+		{
+			name:    "yaml",
+			file:    "config.yaml",
+			content: "prefix: v1\ntimeouts:\n  read: 5s\n",
+			target:  "/v1/ping",
+			code:    http.StatusOK,
+		},
+		{
+			name:    "json",
+			file:    "config.json",
+			content: `{"prefix": "v1", "auth": {"type": "bearer", "token": "secret"}}`,
+			target:  "/v1/ping",
+			code:    http.StatusUnauthorized,
+		},
+		{
+			name:   "environment only",
+			env:    map[string]string{"ENGI_PREFIX": "env"},
+			target: "/env/ping",
+			code:   http.StatusOK,
+		},
+		{
+			name:    "environment overrides file",
+			file:    "config.yaml",
+			content: "prefix: file\n",
+			env:     map[string]string{"ENGI_PREFIX": "env"},
+			target:  "/env/ping",
+			code:    http.StatusOK,
+		},
+		{
+			name:    "unknown yaml key",
+			file:    "config.yaml",
+			content: "prefix: v1\nunknown: 1\n",
+			errs:    []string{"unknown"},
+		},
+		{
+			name:    "unknown json key",
+			file:    "config.json",
+			content: `{"timeouts": {"reed": "1s"}}`,
+			errs:    []string{"reed"},
+		},
+		{
+			name: "unsupported format",
+			file: "config.toml",
+			errs: []string{"unsupported config file format"},
+		},
+		{
+			name:    "invalid duration",
+			file:    "config.yaml",
+			content: "shutdown_timeout: soon\n",
+			errs:    []string{"soon"},
+		},
+		{
+			name: "unknown environment variable",
+			env:  map[string]string{"ENGI_PREFIXX": "v1"},
+			errs: []string{"ENGI_PREFIXX"},
+		},
+		{
+			name: "invalid values are reported at once",
+			env: map[string]string{
+				"ENGI_TIMEOUTS_IDLE": "-1s",
+				"ENGI_LOG_FORMAT":    "html",
+				"ENGI_AUTH_TYPE":     "basic",
+				"ENGI_CORS_METHODS":  "GET",
+			},
+			errs: []string{"timeouts.idle", "log.format", "auth", "cors.origins"},
+		},
+		{
+			name: "unknown auth placing",
+			env:  map[string]string{"ENGI_AUTH_TYPE": "api_key", "ENGI_AUTH_KEY": "k", "ENGI_AUTH_VALUE": "v", "ENGI_AUTH_IN": "body"},
+			errs: []string{"auth.in"},
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			for key, value := range test.env {
+				t.Setenv(key, value)
+			}
+
+			var file string
+			if test.file != "" {
+				file = filepath.Join(t.TempDir(), test.file)
+
+				if err := os.WriteFile(file, []byte(test.content), 0o600); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			options, err := engi.FromConfig(file)
+			if len(test.errs) != 0 {
+				if !errors.Is(err, engi.ErrInvalidConfig) {
+					t.Fatalf("expected '%s', got '%v'", engi.ErrInvalidConfig, err)
+				}
+
+				for _, message := range test.errs {
+					if !strings.Contains(err.Error(), message) {
+						t.Fatalf("expected error to mention '%s', got '%s'", message, err)
+					}
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var e = engi.New(":0", options...)
+			if err := e.RegisterServices(pingAPI{}); err != nil {
+				t.Fatal(err)
+			}
+
+			if recorder := serve(e, http.MethodGet, test.target); recorder.Code != test.code {
+				t.Fatalf("%s: expected %d, got %d (%s)", test.target, test.code, recorder.Code, recorder.Body)
+			}
+		})
+	}
+}
+
+func TestConfigTimeoutsApplyToListeners(t *testing.T) {
+	t.Setenv("ENGI_TIMEOUTS_READ_HEADER", "50ms")
+
+	options, err := engi.FromConfig("")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var (
+		addresses = []string{freeAddress(t), freeAddress(t)}
+		e         = engi.New(addresses[0], append(options, engi.WithListener("tcp", addresses[1]))...)
+		errs      = make(chan error, 1)
+	)
+
+	if err := e.RegisterServices(pingAPI{}); err != nil {
+		t.Fatal(err)
+	}
+
+	go func() { errs <- e.Start() }()
+
+	defer func() {
+		if err := e.Shutdown(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+
+		<-errs
+	}()
+
+	for _, address := range addresses {
+		var (
+			conn net.Conn
+			err  error
+		)
+
+		for deadline := time.Now().Add(5 * time.Second); ; time.Sleep(10 * time.Millisecond) {
+			if conn, err = net.Dial("tcp", address); err == nil || time.Now().After(deadline) {
+				break
+			}
+		}
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// Connection sending no request is closed by server after header timeout.
+		var started = time.Now()
+
+		conn.SetReadDeadline(started.Add(2 * time.Second)) //nolint:errcheck
+
+		if _, err := conn.Read(make([]byte, 1)); err == nil || time.Since(started) > time.Second {
+			t.Fatalf("%s: connection wasn't closed after header timeout (error: %v)", address, err)
+		}
+
+		conn.Close()
+	}
+}
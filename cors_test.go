@@ -0,0 +1,72 @@
+package engi_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/KlyuchnikovV/engi"
+)
+
+type corsAPI struct{}
+
+func (corsAPI) Prefix() string { return "c" }
+
+func (corsAPI) Routers() engi.Routes {
+	var ok = func(_ context.Context, _ engi.Request, response engi.Response) error {
+		return response.OK("ok")
+	}
+
+	return engi.Routes{
+		"public": engi.GET(ok),
+		"admin": engi.Group(engi.Routes{
+			"users": engi.GET(ok),
+			"items": engi.GET(ok, engi.UseCORS(
+				engi.AllowedOrigins("https://items.example"),
+				engi.AllowedMethods(http.MethodGet),
+			)),
+		}, engi.UseCORS(
+			engi.AllowedOrigins("https://admin.example"),
+			engi.AllowedMethods(http.MethodGet),
+		)),
+	}
+}
+
+func TestPreflightUsesRouteCORS(t *testing.T) {
+	var e = engi.New(":0")
+	if err := e.RegisterServices(corsAPI{}); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, test := range []struct {
+		target string
+		origin string
+		code   int
+		allow  string
+	}{
+		{"/c/admin/users", "https://admin.example", http.StatusNoContent, "https://admin.example"},
+		{"/c/admin/users", "https://other.example", http.StatusForbidden, ""},
+		{"/c/admin/items", "https://items.example", http.StatusNoContent, "https://items.example"},
+		{"/c/admin/items", "https://admin.example", http.StatusForbidden, ""},
+		{"/c/public", "https://admin.example", http.StatusNoContent, ""},
+	} {
+		var (
+			recorder = httptest.NewRecorder()
+			request  = httptest.NewRequest(http.MethodOptions, test.target, nil)
+		)
+
+		request.Header.Set("Origin", test.origin)
+		request.Header.Set("Access-Control-Request-Method", http.MethodGet)
+
+		e.ServeHTTP(recorder, request)
+
+		if recorder.Code != test.code {
+			t.Fatalf("%s from %s: expected %d, got %d (%s)", test.target, test.origin, test.code, recorder.Code, recorder.Body)
+		}
+
+		if allow := recorder.Header().Get("Access-Control-Allow-Origin"); allow != test.allow {
+			t.Fatalf("%s from %s: expected allowed origin '%s', got '%s'", test.target, test.origin, test.allow, allow)
+		}
+	}
+}
@@ -1,11 +1,17 @@
 package engi
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"log/slog"
 	"net/http"
 	"os"
+	"os/signal"
 	"strings"
+	"sync"
+	"sync/atomic"
+	"syscall"
 	"time"
 
 	"github.com/KlyuchnikovV/engi/internal/types"
@@ -20,29 +26,46 @@ import (
 // TODO: logging (log url usages)
 
 const (
-	defaultAddress = ":8080"
-	defaultTimeout = 5 * time.Second
+	defaultAddress         = ":8080"
+	defaultTimeout         = 5 * time.Second
+	defaultShutdownTimeout = 10 * time.Second
 )
 
 // Engine - server provider.
 type Engine struct {
 	apiPrefix string
 
-	server *http.Server
+	server    *http.Server
+	listeners []*listener
+	routes    atomic.Pointer[dispatcher]
+
+	// serverTimeouts - timeouts configured for servers of all listeners, listener's own options override them.
+	serverTimeouts []func(*http.Server)
 
 	responseMarshaler types.Marshaler
 	responseObject    types.Responser
 
+	middlewares []Register
+
+	mutex    sync.Mutex
 	services []*Service
+	running  bool
+
+	inFlight        *inFlight
+	shutdownTimeout time.Duration
+	panicHook       PanicHook
+	errorHandler    ErrorHandler
+
+	tlsOptions []tlsOption
+	versioning *versioning
+
+	pathPolicy      PathPolicy
+	caseInsensitive bool
 
 	logger *slog.Logger
 }
 
 func New(address string, configs ...Option) *Engine {
-	if address == "" {
-		address = defaultAddress
-	}
-
 	var engine = &Engine{
 		responseObject:    new(response.AsIs),
 		responseMarshaler: *types.NewJSONMarshaler(),
@@ -53,66 +76,470 @@ func New(address string, configs ...Option) *Engine {
 			IdleTimeout:       defaultTimeout,
 			ReadHeaderTimeout: defaultTimeout,
 		},
-		logger: slog.New(slog.NewTextHandler(os.Stdout, nil)),
+		errorHandler:    DefaultErrorHandler,
+		inFlight:        newInFlight(),
+		shutdownTimeout: defaultShutdownTimeout,
+		logger:          slog.New(slog.NewTextHandler(os.Stdout, nil)),
 	}
 
+	engine.server.Handler = engine
+
 	for _, config := range configs {
 		config(engine)
 	}
 
+	// Default address is used only if no other listeners were configured.
+	if engine.server.Addr == "" && len(engine.listeners) == 0 {
+		engine.server.Addr = defaultAddress
+	}
+
 	return engine
 }
 
+// ServeHTTP - dispatches request to registered services.
+// Allows engine to be served by custom server, embedded into another mux or called directly in tests.
+func (e *Engine) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	escaped, ok := e.normalize(w, r)
+	if !ok {
+		return
+	}
+
+	var (
+		canonical = escaped
+		routes    = e.routes.Load()
+	)
+
+	r = withPath(r, escaped)
+
+	if e.versioning != nil {
+		r, escaped = e.withVersion(w, r, escaped)
+	}
+
+	if routes == nil {
+		e.notFound(w)
+		return
+	}
+
+	if r, escaped, ok = e.trailingSlash(w, r, routes, canonical, escaped); !ok {
+		return
+	}
+
+	if !routes.serve(w, r, escaped) {
+		e.notFound(w)
+	}
+}
+
+// Handler - returns engine as http.Handler serving all registered services.
+func (e *Engine) Handler() http.Handler {
+	return e
+}
+
 // RegisterServices - registering service routes.
+// Services are added to already registered ones, so method can be called several times, even while engine is running.
+// Either all services are registered or none of them if error occurred.
 func (e *Engine) RegisterServices(services ...ServiceAPI) error {
-	e.services = make([]*Service, len(services))
+	var registered = make([]*Service, 0, len(services))
 
-	var mux = http.NewServeMux()
+	for _, service := range services {
+		srv, err := e.newService(service)
+		if err != nil {
+			return err
+		}
 
-	for i, service := range services {
-		var (
-			servicePath = fmt.Sprintf("%s/%s/", e.apiPrefix, service.Prefix())
-			srv         = NewService(e, service, servicePath)
-		)
+		for _, other := range registered {
+			if other.key() == srv.key() {
+				return fmt.Errorf("%w: '%s'", ErrServiceAlreadyRegistered, service.Prefix())
+			}
+		}
 
-		e.services[i] = srv
+		registered = append(registered, srv)
+	}
 
-		for path, register := range service.Routers() {
-			if err := register(e.services[i], strings.Trim(path, "/")); err != nil {
-				return fmt.Errorf("%w, engine: %s", err, strings.Trim(e.apiPrefix, "/"))
-			}
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
 
-			e.services[i].logger.Debug("route registered",
-				slog.String("path", path),
-				slog.String("full_path", fmt.Sprintf("%s%s", servicePath, path)),
-			)
+	for _, srv := range registered {
+		if e.serviceIndex(srv.key()) >= 0 {
+			return fmt.Errorf("%w: '%s'", ErrServiceAlreadyRegistered, srv.Prefix())
 		}
+	}
 
-		mux.HandleFunc(servicePath, func(w http.ResponseWriter, r *http.Request) {
-			var uri, _ = strings.CutPrefix(r.URL.Path, fmt.Sprintf("%s/%s", e.apiPrefix, srv.api.Prefix()))
+	if err := e.checkRoutes(append(append([]*Service{}, e.services...), registered...)); err != nil {
+		return err
+	}
 
-			if err := srv.Serve(w, r, uri); err != nil {
-				srv.logger.Error(err.Error())
-			} else {
-				srv.logger.Debug("request handled")
-			}
-		})
+	if e.running {
+		if err := startServices(context.Background(), registered); err != nil {
+			return err
+		}
+	}
+
+	e.services = append(e.services, registered...)
+	e.rebuild()
+
+	for _, srv := range registered {
+		e.logger.Debug("service registered", slog.String("service", srv.Prefix()))
+	}
+
+	return nil
+}
+
+// ReplaceService - atomically replaces registered service having same prefix (and host or header conditions) with new one.
+// Requests already being handled by old service are finished by it.
+func (e *Engine) ReplaceService(service ServiceAPI) error {
+	srv, err := e.newService(service)
+	if err != nil {
+		return err
+	}
+
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
 
-		e.logger.Debug("service registered", slog.String("service", service.Prefix()))
+	var i = e.serviceIndex(srv.key())
+	if i < 0 {
+		return fmt.Errorf("%w: '%s'", ErrServiceNotFound, service.Prefix())
 	}
 
-	e.server.Handler = mux
+	var services = append([]*Service{}, e.services...)
+	services[i] = srv
+
+	if err := e.checkRoutes(services); err != nil {
+		return err
+	}
+
+	if e.running {
+		if err := startServices(context.Background(), []*Service{srv}); err != nil {
+			return err
+		}
+	}
+
+	var old = e.services[i]
+
+	e.services[i] = srv
+	e.rebuild()
+
+	e.logger.Debug("service replaced", slog.String("service", srv.Prefix()))
+
+	if e.running {
+		return stopServices(context.Background(), []*Service{old})
+	}
+
+	return nil
+}
+
+// UnregisterService - removes services with prefix (all of them if they serve different hosts or headers),
+// their routes are no longer served.
+func (e *Engine) UnregisterService(prefix string) error {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	var (
+		services = make([]*Service, 0, len(e.services))
+		removed  = make([]*Service, 0, 1)
+	)
+
+	for _, srv := range e.services {
+		if srv.Prefix() == trimPrefix(prefix) {
+			removed = append(removed, srv)
+		} else {
+			services = append(services, srv)
+		}
+	}
+
+	if len(removed) == 0 {
+		return fmt.Errorf("%w: '%s'", ErrServiceNotFound, prefix)
+	}
+
+	e.services = services
+	e.rebuild()
+
+	e.logger.Debug("service unregistered", slog.String("service", trimPrefix(prefix)))
+
+	if e.running {
+		return stopServices(context.Background(), removed)
+	}
+
+	return nil
+}
+
+// newService - creates service and registers its routes.
+func (e *Engine) newService(service ServiceAPI) (*Service, error) {
+	var (
+		servicePath = e.servicePath(service.Prefix())
+		srv         = NewService(e, service, servicePath)
+	)
+
+	for path, register := range service.Routers() {
+		if err := register(srv, strings.Trim(path, "/")); err != nil {
+			return nil, fmt.Errorf("%w, engine: %s", err, strings.Trim(e.apiPrefix, "/"))
+		}
+
+		srv.logger.Debug("route registered",
+			slog.String("path", path),
+			slog.String("full_path", fmt.Sprintf("%s%s", servicePath, path)),
+		)
+	}
+
+	children, err := srv.addChildren("", service)
+	if err != nil {
+		return nil, fmt.Errorf("%w, engine: %s", err, strings.Trim(e.apiPrefix, "/"))
+	}
+
+	srv.components = append(srv.components, children...)
+
+	return srv, nil
+}
+
+// checkRoutes - checks that route names of services are unique and,
+// if engine is running, routes referenced with Links are registered.
+// Must be called under lock.
+func (e *Engine) checkRoutes(services []*Service) error {
+	if err := checkNames(services); err != nil {
+		return err
+	}
+
+	if e.running {
+		return checkLinks(services)
+	}
 
 	return nil
 }
 
-// Start listens on the TCP network address srv.Addr and then calls Serve to handle requests on incoming connections.
-// Accepted connections are configured to enable TCP keep-alives.
+// rebuild - creates new dispatcher for registered services and swaps it with current one.
+// Services sharing path are tried in order of specificity of their host, header or version conditions,
+// service without conditions is the last one.
+// Must be called under lock.
+func (e *Engine) rebuild() {
+	e.routes.Store(newDispatcher(e.services, e.caseInsensitive))
+}
+
+// servicePath - returns path of service with prefix, service with empty prefix is served at api's root.
+func (e *Engine) servicePath(prefix string) string {
+	if prefix = trimPrefix(prefix); prefix == "" {
+		return e.apiPrefix + "/"
+	}
+
+	return fmt.Sprintf("%s/%s/", e.apiPrefix, prefix)
+}
+
+// serviceIndex - returns index of registered service with key or -1.
+// Must be called under lock.
+func (e *Engine) serviceIndex(key string) int {
+	for i, srv := range e.services {
+		if srv.key() == key {
+			return i
+		}
+	}
+
+	return -1
+}
+
+func trimPrefix(prefix string) string {
+	return strings.Trim(prefix, "/")
+}
+
+// Start listens on the TCP network address srv.Addr and on additional listeners
+// and then calls Serve to handle requests on incoming connections.
+// Accepted connections are configured to enable TCP keep-alives. If any TLS option was provided, connections are served over TLS.
+//
+// Before serving, services implementing Initializer and Starter are initialized and started in registration order,
+// failed initialization aborts start. Start also fails if any route name referenced with Links is not registered.
 //
 // Start always returns a non-nil error. After Shutdown or Close, the returned error is ErrServerClosed.
 func (e *Engine) Start() error {
-	e.logger.Info("Starting engi", slog.String("address", e.server.Addr))
+	if err := e.setupTLS(); err != nil {
+		return err
+	}
+
+	listeners, err := e.listen()
+	if err != nil {
+		return err
+	}
+
+	if err := e.startServices(); err != nil {
+		for _, listener := range listeners {
+			listener.Close()
+		}
+
+		return err
+	}
+
+	var (
+		errs      = make(chan error, len(listeners))
+		addresses = make([]string, 0, len(listeners))
+		// Servers change their TLS configs setting up HTTP/2, so it's checked before serving.
+		useTLS = e.server.TLSConfig != nil
+	)
+
+	for _, listener := range listeners {
+		addresses = append(addresses, listener.Addr().String())
+	}
+
+	e.logger.Info("Starting engi",
+		slog.Any("address", addresses),
+		slog.Bool("tls", useTLS),
+	)
+
+	for _, listener := range listeners {
+		go func(listener boundListener) {
+			errs <- listener.serve()
+		}(listener)
+	}
+
 	e.logger.Info("engi started...")
 
-	return e.server.ListenAndServe()
+	if err = <-errs; !errors.Is(err, http.ErrServerClosed) {
+		for _, server := range e.servers() {
+			server.Close()
+		}
+
+		return errors.Join(err, e.stopServices(context.Background()))
+	}
+
+	return err
+}
+
+// startServices - calls lifecycle hooks of registered services on engine start.
+func (e *Engine) startServices() error {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	if err := checkLinks(e.services); err != nil {
+		return err
+	}
+
+	if err := startServices(context.Background(), e.services); err != nil {
+		return err
+	}
+
+	e.running = true
+
+	return nil
+}
+
+// stopServices - calls lifecycle hooks of registered services on engine stop.
+func (e *Engine) stopServices(ctx context.Context) error {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	if !e.running {
+		return nil
+	}
+
+	e.running = false
+
+	// Stop hooks get their own deadline, so services can release resources
+	// even if draining requests used up the whole shutdown deadline.
+	ctx = context.WithoutCancel(ctx)
+
+	if e.shutdownTimeout > 0 {
+		var cancel context.CancelFunc
+
+		ctx, cancel = context.WithTimeout(ctx, e.shutdownTimeout)
+		defer cancel()
+	}
+
+	return stopServices(ctx, e.services)
+}
+
+// StartAndWait - starts server and blocks until SIGINT or SIGTERM is received,
+// then gracefully shuts server down waiting for in-flight requests no longer than shutdown timeout.
+//
+// Returns nil if server was stopped by signal and all requests were drained.
+func (e *Engine) StartAndWait() error {
+	var (
+		ctx, stop = signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+		errs      = make(chan error, 1)
+	)
+	defer stop()
+
+	go func() {
+		errs <- e.Start()
+	}()
+
+	select {
+	case err := <-errs:
+		if errors.Is(err, http.ErrServerClosed) {
+			return nil
+		}
+
+		return err
+	case <-ctx.Done():
+		stop()
+	}
+
+	e.logger.Info("signal received, shutting down", slog.Duration("timeout", e.shutdownTimeout))
+
+	// Zero timeout means waiting for in-flight requests without deadline.
+	ctx = context.Background()
+
+	if e.shutdownTimeout > 0 {
+		var cancel context.CancelFunc
+
+		ctx, cancel = context.WithTimeout(ctx, e.shutdownTimeout)
+		defer cancel()
+	}
+
+	return e.Shutdown(ctx)
+}
+
+// Shutdown - stops accepting new connections and waits for in-flight requests to finish,
+// then stops services implementing Stopper in reverse registration order.
+// Stoppers get context keeping values of 'ctx' but having own deadline set by WithShutdownTimeout.
+// If 'ctx' expires before requests are drained, returns ErrShutdownTimeout listing routes that were still running.
+func (e *Engine) Shutdown(ctx context.Context) error {
+	e.logger.Info("Stopping engi")
+
+	if err := e.shutdownServers(ctx); err != nil {
+		if !errors.Is(err, context.DeadlineExceeded) && !errors.Is(err, context.Canceled) {
+			return errors.Join(err, e.stopServices(ctx))
+		}
+
+		var running = e.inFlight.running()
+
+		e.logger.Warn("shutdown deadline reached",
+			slog.Any("running_routes", running),
+		)
+
+		return errors.Join(
+			fmt.Errorf("%w: still running: [%s]", ErrShutdownTimeout, strings.Join(running, ", ")),
+			e.stopServices(ctx),
+		)
+	}
+
+	if err := e.stopServices(ctx); err != nil {
+		return err
+	}
+
+	e.logger.Info("engi stopped")
+
+	return nil
+}
+
+// shutdownServers - gracefully shuts down all servers in parallel.
+func (e *Engine) shutdownServers(ctx context.Context) error {
+	var (
+		servers = e.servers()
+		errs    = make([]error, len(servers))
+		wg      sync.WaitGroup
+	)
+
+	for i, server := range servers {
+		wg.Add(1)
+
+		go func(i int, server *http.Server) {
+			defer wg.Done()
+
+			errs[i] = server.Shutdown(ctx)
+		}(i, server)
+	}
+
+	wg.Wait()
+
+	return errors.Join(errs...)
+}
+
+// Running - returns routes having requests in progress in format 'METHOD /path'.
+func (e *Engine) Running() []string {
+	return e.inFlight.running()
 }
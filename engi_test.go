@@ -0,0 +1,260 @@
+package engi_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/KlyuchnikovV/engi"
+)
+
+// routesAPI - service with routes set by test.
+type routesAPI struct {
+	prefix string
+	routes engi.Routes
+}
+
+func (api routesAPI) Prefix() string { return api.prefix }
+
+func (api routesAPI) Routers() engi.Routes { return api.routes }
+
+func TestEngineAsHandler(t *testing.T) {
+	var e = engi.New(":0", engi.WithPrefix("api"))
+
+	if err := e.RegisterServices(pingAPI{}); err != nil {
+		t.Fatal(err)
+	}
+
+	var mux = http.NewServeMux()
+
+	mux.Handle("/api/", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Wrapped", "true")
+		e.Handler().ServeHTTP(w, r)
+	}))
+	mux.HandleFunc("/health", func(w http.ResponseWriter, _ *http.Request) {
+		w.Write([]byte("ok")) //nolint:errcheck
+	})
+
+	for _, test := range []struct {
+		name    string
+		handler http.Handler
+		target  string
+		code    int
+		body    string
+		wrapped bool
+	}{
+		{"engine", e, "/api/ping", http.StatusOK, "pong", false},
+		{"engine unknown path", e, "/api/unknown", http.StatusNotFound, "", false},
+		{"mux", mux, "/api/ping", http.StatusOK, "pong", true},
+		{"mux unknown path", mux, "/api/unknown", http.StatusNotFound, "", true},
+		{"mux own handler", mux, "/health", http.StatusOK, "ok", false},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			var recorder = httptest.NewRecorder()
+
+			test.handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, test.target, nil))
+
+			if recorder.Code != test.code {
+				t.Fatalf("expected %d, got %d (%s)", test.code, recorder.Code, recorder.Body)
+			}
+
+			if !strings.Contains(recorder.Body.String(), test.body) {
+				t.Fatalf("expected body containing '%s', got '%s'", test.body, recorder.Body)
+			}
+
+			if wrapped := recorder.Header().Get("Wrapped") == "true"; wrapped != test.wrapped {
+				t.Fatalf("expected wrapped %t, got %t", test.wrapped, wrapped)
+			}
+		})
+	}
+}
+
+// reply - returns route responding with text.
+func reply(text string) engi.RouteByPath {
+	return engi.GET(func(_ context.Context, _ engi.Request, response engi.Response) error {
+		return response.OK(text)
+	})
+}
+
+func TestRegistrationErrors(t *testing.T) {
+	for _, test := range []struct {
+		name    string
+		batches [][]engi.ServiceAPI
+		err     error
+		message string
+	}{
+		{
+			name: "same prefix in one call",
+			batches: [][]engi.ServiceAPI{{
+				routesAPI{"a", engi.Routes{"x": reply("1")}},
+				routesAPI{"/a/", engi.Routes{"y": reply("2")}},
+			}},
+			err: engi.ErrServiceAlreadyRegistered,
+		},
+		{
+			name: "same prefix in next call",
+			batches: [][]engi.ServiceAPI{
+				{routesAPI{"a", engi.Routes{"x": reply("1")}}},
+				{routesAPI{"a", engi.Routes{"y": reply("2")}}},
+			},
+			err: engi.ErrServiceAlreadyRegistered,
+		},
+		{
+			name: "same method and path",
+			batches: [][]engi.ServiceAPI{{
+				routesAPI{"a", engi.Routes{"x": reply("1"), "/x/": reply("2")}},
+			}},
+			err: engi.ErrRouteAlreadyRegistered,
+		},
+		{
+			name: "same path with different parameter names",
+			batches: [][]engi.ServiceAPI{{
+				routesAPI{"a", engi.Routes{"get/{id}": reply("1"), "get/{name}": reply("2")}},
+			}},
+			message: "route is ambiguous",
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			var (
+				e   = engi.New(":0")
+				err error
+			)
+
+			for _, batch := range test.batches {
+				if err = e.RegisterServices(batch...); err != nil {
+					break
+				}
+			}
+
+			if test.err != nil && !errors.Is(err, test.err) {
+				t.Fatalf("expected '%s', got '%v'", test.err, err)
+			}
+
+			if test.message != "" && (err == nil || !strings.Contains(err.Error(), test.message)) {
+				t.Fatalf("expected '%s', got '%v'", test.message, err)
+			}
+
+			// Failed batch is not registered at all, previous ones are kept.
+			if recorder := serve(e, http.MethodGet, "/b/x"); recorder.Code != http.StatusNotFound {
+				t.Fatalf("expected failed batch not registered, got %d", recorder.Code)
+			}
+
+			if len(test.batches) > 1 {
+				if recorder := serve(e, http.MethodGet, "/a/x"); recorder.Code != http.StatusOK {
+					t.Fatalf("expected previous batch served, got %d", recorder.Code)
+				}
+			}
+		})
+	}
+}
+
+func TestRegistrationIsAdditive(t *testing.T) {
+	var e = engi.New(":0")
+
+	for _, prefix := range []string{"a", "b"} {
+		if err := e.RegisterServices(routesAPI{prefix, engi.Routes{"x": reply(prefix)}}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for _, target := range []string{"/a/x", "/b/x"} {
+		if recorder := serve(e, http.MethodGet, target); recorder.Code != http.StatusOK {
+			t.Fatalf("%s: expected %d, got %d", target, http.StatusOK, recorder.Code)
+		}
+	}
+}
+
+// blockingAPI - service which route blocks until released, so requests can be kept in flight.
+type blockingAPI struct {
+	text    string
+	started chan struct{}
+	release chan struct{}
+}
+
+func (api blockingAPI) Prefix() string { return "b" }
+
+func (api blockingAPI) Routers() engi.Routes {
+	return engi.Routes{
+		"x": engi.GET(func(_ context.Context, _ engi.Request, response engi.Response) error {
+			if api.started != nil {
+				close(api.started)
+				<-api.release
+			}
+
+			return response.OK(api.text)
+		}),
+	}
+}
+
+func TestChangingServicesWithRequestsInFlight(t *testing.T) {
+	for _, test := range []struct {
+		name   string
+		change func(*engi.Engine) error
+		code   int
+		body   string
+	}{
+		{
+			name:   "replace",
+			change: func(e *engi.Engine) error { return e.ReplaceService(blockingAPI{text: "new"}) },
+			code:   http.StatusOK,
+			body:   "new",
+		},
+		{
+			name:   "unregister",
+			change: func(e *engi.Engine) error { return e.UnregisterService("/b/") },
+			code:   http.StatusNotFound,
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			var (
+				e   = engi.New(":0")
+				old = blockingAPI{text: "old", started: make(chan struct{}), release: make(chan struct{})}
+				in  = make(chan *httptest.ResponseRecorder, 1)
+			)
+
+			if err := e.RegisterServices(old); err != nil {
+				t.Fatal(err)
+			}
+
+			go func() { in <- serve(e, http.MethodGet, "/b/x") }()
+
+			select {
+			case <-old.started:
+			case <-time.After(5 * time.Second):
+				t.Fatal("request was not started")
+			}
+
+			if err := test.change(e); err != nil {
+				t.Fatal(err)
+			}
+
+			var recorder = serve(e, http.MethodGet, "/b/x")
+			if recorder.Code != test.code || !strings.Contains(recorder.Body.String(), test.body) {
+				t.Fatalf("expected %d '%s', got %d (%s)", test.code, test.body, recorder.Code, recorder.Body)
+			}
+
+			close(old.release)
+
+			// Request in flight is finished by old service.
+			if recorder = <-in; recorder.Code != http.StatusOK || !strings.Contains(recorder.Body.String(), "old") {
+				t.Fatalf("expected in-flight request finished by old service, got %d (%s)", recorder.Code, recorder.Body)
+			}
+		})
+	}
+}
+
+func TestChangingUnknownService(t *testing.T) {
+	var e = engi.New(":0")
+
+	if err := e.ReplaceService(pingAPI{}); !errors.Is(err, engi.ErrServiceNotFound) {
+		t.Fatalf("expected '%s', got '%v'", engi.ErrServiceNotFound, err)
+	}
+
+	if err := e.UnregisterService("ping"); !errors.Is(err, engi.ErrServiceNotFound) {
+		t.Fatalf("expected '%s', got '%v'", engi.ErrServiceNotFound, err)
+	}
+}
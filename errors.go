@@ -0,0 +1,160 @@
+package engi
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/KlyuchnikovV/engi/response"
+)
+
+type (
+	// ErrorHandler - responds to client with error returned from route.
+	ErrorHandler func(ctx context.Context, request Request, response Response, err error) error
+
+	// StatusCoder - error defining http code it should be responded with.
+	StatusCoder interface {
+		StatusCode() int
+	}
+
+	// PublicError - error defining message safe to be shown to client.
+	PublicError interface {
+		PublicMessage() string
+	}
+
+	// DetailedError - error having details for client (e.g. list of invalid fields).
+	DetailedError interface {
+		ErrorDetails() interface{}
+	}
+
+	// Error - error with http code, public message and details, can wrap internal error which is not shown to client.
+	//
+	// Can be used to define domain errors:
+	//
+	//	var ErrNotFound = engi.NewError(http.StatusNotFound, "not found")
+	//
+	//	return fmt.Errorf("%w: note %d", ErrNotFound, id)
+	Error struct {
+		Code    int
+		Message string
+		Details interface{}
+		Err     error
+	}
+)
+
+// NewError - creates error responded with code and message.
+func NewError(code int, message string) *Error {
+	return &Error{
+		Code:    code,
+		Message: message,
+	}
+}
+
+// WithDetails - returns copy of error with details.
+func (e *Error) WithDetails(details interface{}) *Error {
+	var err = *e
+	err.Details = details
+
+	return &err
+}
+
+// Wrap - returns copy of error wrapping internal error.
+func (e *Error) Wrap(err error) *Error {
+	var wrapped = *e
+	wrapped.Err = err
+
+	return &wrapped
+}
+
+func (e *Error) Error() string {
+	if e.Err != nil {
+		return e.Message + ": " + e.Err.Error()
+	}
+
+	return e.Message
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Is - errors created from the same error (e.g. with Wrap or WithDetails) are treated as equal.
+func (e *Error) Is(target error) bool {
+	typed, ok := target.(*Error)
+
+	return ok && typed.Code == e.Code && typed.Message == e.Message
+}
+
+func (e *Error) StatusCode() int {
+	return e.Code
+}
+
+func (e *Error) PublicMessage() string {
+	return e.Message
+}
+
+func (e *Error) ErrorDetails() interface{} {
+	return e.Details
+}
+
+// WithErrorHandler - sets handler responding to client with errors returned from routes.
+func WithErrorHandler(handler ErrorHandler) Option {
+	return func(engine *Engine) {
+		engine.errorHandler = handler
+	}
+}
+
+// DefaultErrorHandler - responds with code, public message and details of typed error found with errors.As
+// (StatusCoder, PublicError, DetailedError or 'response.AsObject').
+// Any other error is responded with 500 and its message isn't shown to client.
+func DefaultErrorHandler(_ context.Context, _ Request, response Response, err error) error {
+	var code, message, details = resolveError(err)
+
+	return response.ErrorWithDetails(code, message, details)
+}
+
+// resolveError - returns code, public message and details error should be responded with.
+func resolveError(err error) (int, string, interface{}) {
+	var (
+		coder    StatusCoder
+		public   PublicError
+		detailed DetailedError
+		object   *response.AsObject
+		value    response.AsObject
+	)
+
+	switch {
+	case errors.As(err, &coder):
+		var (
+			code    = statusOrDefault(coder.StatusCode())
+			message = http.StatusText(code)
+			details interface{}
+		)
+
+		if errors.As(err, &public) {
+			message = public.PublicMessage()
+		}
+
+		if errors.As(err, &detailed) {
+			details = detailed.ErrorDetails()
+		}
+
+		return code, message, details
+	case errors.As(err, &object):
+		return statusOrDefault(object.Code), object.ErrorString, object.Details
+	case errors.As(err, &value):
+		return statusOrDefault(value.Code), value.ErrorString, value.Details
+	case errors.Is(err, context.DeadlineExceeded):
+		return http.StatusGatewayTimeout, http.StatusText(http.StatusGatewayTimeout), nil
+	default:
+		return http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError), nil
+	}
+}
+
+func statusOrDefault(code int) int {
+	if code == 0 {
+		return http.StatusInternalServerError
+	}
+
+	return code
+}
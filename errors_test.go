@@ -0,0 +1,127 @@
+package engi_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/KlyuchnikovV/engi"
+	"github.com/KlyuchnikovV/engi/response"
+)
+
+// codeError - error defining only its http code.
+type codeError int
+
+func (e codeError) Error() string   { return "internal details" }
+func (e codeError) StatusCode() int { return int(e) }
+
+// publicError - error defining only its public message.
+type publicError string
+
+func (e publicError) Error() string         { return "internal details" }
+func (e publicError) PublicMessage() string { return string(e) }
+
+// typedError - error defining its http code, public message and details.
+type typedError struct {
+	codeError
+	message string
+	fields  []string
+}
+
+func (e typedError) PublicMessage() string     { return e.message }
+func (e typedError) ErrorDetails() interface{} { return e.fields }
+
+// failing - returns route failing with error.
+func failing(err error) engi.RouteByPath {
+	return engi.GET(func(context.Context, engi.Request, engi.Response) error {
+		return err
+	})
+}
+
+func TestErrorMapping(t *testing.T) {
+	var (
+		notFound = engi.NewError(http.StatusNotFound, "note not found")
+		e        = engi.New(":0", engi.WithResponse(new(response.AsObject)))
+	)
+
+	if err := e.RegisterServices(routesAPI{"e", engi.Routes{
+		"coder":        failing(fmt.Errorf("wrapped: %w", codeError(http.StatusTeapot))),
+		"zero-code":    failing(codeError(0)),
+		"public":       failing(publicError("public message")),
+		"domain":       failing(fmt.Errorf("%w: note 5", notFound)),
+		"wrapped":      failing(notFound.Wrap(errors.New("sql: no rows"))),
+		"details":      failing(engi.NewError(http.StatusBadRequest, "invalid").WithDetails([]string{"name"})),
+		"object":       failing(response.AsError(http.StatusConflict, "conflict %d", 5)),
+		"object-value": failing(fmt.Errorf("wrapped: %w", response.AsObject{Code: http.StatusGone, ErrorString: "gone"})),
+		"deadline":     failing(fmt.Errorf("query: %w", context.DeadlineExceeded)),
+		"plain":        failing(errors.New("internal details")),
+		"typed":        failing(typedError{codeError(http.StatusForbidden), "access denied", []string{"role"}}),
+	}}); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, test := range []struct {
+		target  string
+		code    int
+		message string
+		details string
+	}{
+		{"/e/coder", http.StatusTeapot, http.StatusText(http.StatusTeapot), "<nil>"},
+		{"/e/zero-code", http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError), "<nil>"},
+		{"/e/public", http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError), "<nil>"},
+		{"/e/typed", http.StatusForbidden, "access denied", "[role]"},
+		{"/e/domain", http.StatusNotFound, "note not found", "<nil>"},
+		{"/e/wrapped", http.StatusNotFound, "note not found", "<nil>"},
+		{"/e/details", http.StatusBadRequest, "invalid", "[name]"},
+		{"/e/object", http.StatusConflict, "conflict 5", "<nil>"},
+		{"/e/object-value", http.StatusGone, "gone", "<nil>"},
+		{"/e/deadline", http.StatusGatewayTimeout, http.StatusText(http.StatusGatewayTimeout), "<nil>"},
+		{"/e/plain", http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError), "<nil>"},
+	} {
+		t.Run(test.target, func(t *testing.T) {
+			var (
+				recorder = serve(e, http.MethodGet, test.target)
+				body     response.AsObject
+			)
+
+			if recorder.Code != test.code {
+				t.Fatalf("expected %d, got %d (%s)", test.code, recorder.Code, recorder.Body)
+			}
+
+			if err := json.Unmarshal(recorder.Body.Bytes(), &body); err != nil {
+				t.Fatalf("decoding '%s' failed: %s", recorder.Body, err)
+			}
+
+			if body.ErrorString != test.message {
+				t.Fatalf("expected message '%s', got '%s'", test.message, body.ErrorString)
+			}
+
+			if details := fmt.Sprint(body.Details); details != test.details {
+				t.Fatalf("expected details '%s', got '%s'", test.details, details)
+			}
+		})
+	}
+}
+
+func TestCustomErrorHandler(t *testing.T) {
+	var e = engi.New(":0", engi.WithErrorHandler(
+		func(_ context.Context, _ engi.Request, response engi.Response, err error) error {
+			return response.Error(http.StatusBadGateway, "custom: "+err.Error())
+		},
+	))
+
+	if err := e.RegisterServices(routesAPI{"e", engi.Routes{
+		"plain": failing(errors.New("failed")),
+	}}); err != nil {
+		t.Fatal(err)
+	}
+
+	var recorder = serve(e, http.MethodGet, "/e/plain")
+
+	if recorder.Code != http.StatusBadGateway {
+		t.Fatalf("expected %d, got %d (%s)", http.StatusBadGateway, recorder.Code, recorder.Body)
+	}
+}
@@ -1,5 +1,7 @@
 package entity
 
+import "time"
+
 type NotesRequest struct {
 	Note   string `description:"Note content in Markdown" example:"# Heading level 1" json:"note"`
 	Author string `description:"Author name"              example:"John Cane"         json:"author"`
@@ -12,3 +14,11 @@ type RequestBody struct {
 	ArrayOfArray [][]float32 `json:"arrayOfArray"`
 	WithoutTag   float64
 }
+
+type FilterParams struct {
+	Bool  bool      `query:"bool,required"`
+	Float float64   `query:"float,required"`
+	Int   int64     `query:"int,required"`
+	Str   string    `query:"str,required"`
+	Time  time.Time `query:"time,required" layout:"2006-01-02 15:04"`
+}
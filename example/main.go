@@ -29,7 +29,7 @@ func main() {
 		log.Fatal(err)
 	}
 
-	if err := w.Start(); err != nil {
+	if err := w.StartAndWait(); err != nil {
 		log.Fatal(err)
 	}
 }
@@ -29,10 +29,10 @@ func (api *NotesAPI) Middlewares() []engi.Register {
 func (api *NotesAPI) Routers() engi.Routes {
 	return engi.Routes{
 		"create": engi.POST(api.Create,
-			parameter.Body(new(entity.NotesRequest)),
+			parameter.BodyOf[entity.NotesRequest](),
 			engi.UseAuthorization(engi.BasicAuth("Dave", "NotCrazy")),
 		),
-		"get/{id}": engi.GET(api.GetByID,
+		"get/{id:int}": engi.GET(api.GetByID,
 			path.Integer("id",
 				validate.AND(validate.Greater(1), validate.Less(10)),
 			),
@@ -50,7 +50,7 @@ func (api *NotesAPI) Create(
 	request engi.Request,
 	response engi.Response,
 ) error {
-	if body := request.Body(); body != nil {
+	if body, ok := engi.BodyAs[entity.NotesRequest](request); ok {
 		return response.OK(body)
 	}
 
@@ -34,20 +34,24 @@ func (api *RequestAPI) Routers() engi.Routes {
 				validate.AND(validate.Greater(1), validate.Less(10)),
 			),
 		),
+		"list": engi.GET(api.GetByIDs,
+			query.Integers("id", ",",
+				validate.MinItems(1), validate.MaxItems(10), validate.Unique,
+				validate.Each(validate.Greater(0)),
+			),
+			query.Integer("limit", parameter.Default(10), validate.Greater(0)),
+		),
 		"create": engi.POST(api.Create,
-			parameter.Body(new(entity.RequestBody)),
+			parameter.BodyOf[entity.RequestBody](),
 		),
 		"create/sub-request": engi.POST(api.CreateSubRequest,
-			parameter.Body([]entity.RequestBody{}),
+			parameter.BodyOf[[]entity.RequestBody](),
 		),
 		"filter": engi.GET(api.Filter,
-			query.Bool("bool"),
-			query.Float("float", validate.NotEmpty),
-			query.Integer("int"),
-			query.String("str",
-				validate.AND(validate.NotEmpty, validate.Greater(2)),
+			parameter.Bind[entity.FilterParams](
+				parameter.Validate("float", validate.NotEmpty),
+				parameter.Validate("str", validate.AND(validate.NotEmpty, validate.Greater(2))),
 			),
-			query.Time("time", "2006-01-02 15:04"),
 		),
 	}
 }
@@ -66,11 +70,13 @@ func (api *RequestAPI) Create(
 
 func (api *RequestAPI) CreateSubRequest(
 	_ context.Context,
-	_ engi.Request,
+	request engi.Request,
 	response engi.Response,
 ) error {
+	body, _ := engi.BodyAs[[]entity.RequestBody](request)
+
 	return response.Object(http.StatusCreated,
-		fmt.Sprintf("sub request created with body %#v", []entity.RequestBody{}),
+		fmt.Sprintf("sub request created with body %#v", body),
 	)
 }
 
@@ -89,21 +95,28 @@ func (api *RequestAPI) GetByID(
 	return response.OK(fmt.Sprintf("got id: '%d'", id))
 }
 
-func (api *RequestAPI) Filter(
+func (api *RequestAPI) GetByIDs(
 	_ context.Context,
 	request engi.Request,
 	response engi.Response,
 ) error {
 	var (
-		i     = request.Integer("int", placing.InQuery)
-		str   = request.String("str", placing.InQuery)
-		t     = request.Time("time", "2006-01-02 15:04", placing.InQuery)
-		b     = request.Bool("bool", placing.InQuery)
-		float = request.Float("float", placing.InQuery)
+		ids   = request.Integers("id", placing.InQuery)
+		limit = request.Integer("limit", placing.InQuery)
 	)
 
+	return response.OK(fmt.Sprintf("got ids: %v (limit: %d)", ids, limit))
+}
+
+func (api *RequestAPI) Filter(
+	_ context.Context,
+	request engi.Request,
+	response engi.Response,
+) error {
+	params, _ := engi.Params[entity.FilterParams](request)
+
 	return response.OK(fmt.Sprintf(
 		"filtered by id: '%d' and field: %s, time: %s, isAssignable: %t and float: %f",
-		i, str, t.Format("15:04 02/01/2006"), b, float,
+		params.Int, params.Str, params.Time.Format("15:04 02/01/2006"), params.Bool, params.Float,
 	))
 }
@@ -0,0 +1,97 @@
+package engi
+
+import (
+	"strings"
+)
+
+// ChildrenAPI - optional interface of ServiceAPI providing nested services.
+// Routes of child service are served under parent's path ('/api/parent/child/...')
+// and use parent's middlewares followed by child's own ones.
+type ChildrenAPI interface {
+	Children() []ServiceAPI
+}
+
+// Group - registers routes under common path (the key of group in Routes) sharing middlewares.
+// Group middlewares are applied after service's ones and before route's ones.
+//
+//	"admin": engi.Group(engi.Routes{
+//		"users": engi.GET(api.Users),
+//	}, engi.UseAuthorization(engi.BasicAuth("admin", "secret"))),
+func Group(routes Routes, middlewares ...Register) RouteByPath {
+	return func(srv *Service, path string) error {
+		var group = srv.withMiddlewares(middlewares)
+
+		for subPath, register := range routes {
+			if err := register(group, joinPath(path, subPath)); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+}
+
+// withMiddlewares - returns service registering routes into same handlers with additional middlewares.
+func (srv *Service) withMiddlewares(middlewares []Register) *Service {
+	var group = *srv
+
+	group.groupMiddlewares = make([]Register, 0, len(srv.groupMiddlewares)+len(middlewares))
+	group.groupMiddlewares = append(group.groupMiddlewares, srv.groupMiddlewares...)
+	group.groupMiddlewares = append(group.groupMiddlewares, middlewares...)
+
+	return &group
+}
+
+// addChildren - registers routes of nested services recursively, returns all nested services.
+func (srv *Service) addChildren(path string, api ServiceAPI) ([]component, error) {
+	parent, ok := api.(ChildrenAPI)
+	if !ok {
+		return nil, nil
+	}
+
+	var children = make([]component, 0)
+
+	for _, child := range parent.Children() {
+		var (
+			childPath = joinPath(path, child.Prefix())
+			group     = srv
+		)
+
+		if middlewaresAPI, ok := child.(MiddlewaresAPI); ok {
+			group = srv.withMiddlewares(middlewaresAPI.Middlewares())
+		}
+
+		for subPath, register := range child.Routers() {
+			if err := register(group, joinPath(childPath, subPath)); err != nil {
+				return nil, err
+			}
+		}
+
+		grandchildren, err := group.addChildren(childPath, child)
+		if err != nil {
+			return nil, err
+		}
+
+		children = append(children, component{
+			name:   joinPath(srv.Prefix(), childPath),
+			api:    child,
+			logger: srv.logger,
+		})
+		children = append(children, grandchildren...)
+	}
+
+	return children, nil
+}
+
+// joinPath - joins path parts with single slash skipping empty ones.
+func joinPath(parts ...string) string {
+	var result = make([]string, 0, len(parts))
+
+	for _, part := range parts {
+		if part = strings.Trim(part, "/"); part != "" {
+			result = append(result, part)
+		}
+	}
+
+	return strings.Join(result, "/")
+}
@@ -0,0 +1,128 @@
+package engi
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/KlyuchnikovV/engi/internal/middlewares"
+)
+
+// mountMethods - methods mounted handler is registered for.
+var mountMethods = []string{
+	http.MethodGet,
+	http.MethodHead,
+	http.MethodPost,
+	http.MethodPut,
+	http.MethodPatch,
+	http.MethodDelete,
+	http.MethodConnect,
+	http.MethodOptions,
+	http.MethodTrace,
+}
+
+// Handler - implements api method call served by http.Handler, e.g. legacy handler or 'httputil.ReverseProxy'.
+// Handler goes through engine's, service's and route's middlewares and gets request with route's context.
+func Handler(method string, handler http.Handler, middlewares ...Register) RouteByPath {
+	return func(srv *Service, path string) error {
+		return srv.add(method, path, handle(handler), middlewares...)
+	}
+}
+
+// HandlerFunc - implements api method call served by http.HandlerFunc.
+func HandlerFunc(method string, handler http.HandlerFunc, middlewares ...Register) RouteByPath {
+	return Handler(method, handler, middlewares...)
+}
+
+// Mount - serves path and all its sub-paths with http.Handler for any method.
+// Service's path followed by 'prefix' (relative to service) is stripped from request's path
+// the same way http.StripPrefix does (case-insensitively if CaseInsensitivePaths is set),
+// requests not having that prefix are responded with 404.
+//
+//	"debug/pprof": engi.Mount("", http.HandlerFunc(pprof.Index)), // handler gets '/debug/pprof/...'
+//	"legacy": engi.Mount("legacy", legacyMux),                     // handler gets '/...'
+func Mount(prefix string, handler http.Handler, middlewares ...Register) RouteByPath {
+	return func(srv *Service, path string) error {
+		var (
+			stripped = strings.TrimSuffix(srv.path+strings.Trim(prefix, "/"), "/")
+			route    = handle(stripPrefix(stripped, srv.caseInsensitive, handler))
+		)
+
+		return srv.addTree(mountMethods, path, route, middlewares...)
+	}
+}
+
+// addTree - registers route for path and all its sub-paths.
+func (srv *Service) addTree(methods []string, path string, route Route, middlewares ...Register) error {
+	var options = middlewares
+
+	for _, method := range methods {
+		for _, pattern := range []string{path, joinPath(path, "{path...}")} {
+			if err := srv.add(method, pattern, route, options...); err != nil {
+				return err
+			}
+
+			// Route's name refers to the first registered pattern only.
+			options = append(middlewares[:len(middlewares):len(middlewares)], unnamed)
+		}
+	}
+
+	return nil
+}
+
+// unnamed - removes name set by route's middlewares.
+func unnamed(middlewares *middlewares.Middlewares) {
+	middlewares.SetName("")
+}
+
+// stripPrefix - works as http.StripPrefix, but compares prefix case-insensitively if 'fold' is set.
+func stripPrefix(prefix string, fold bool, handler http.Handler) http.Handler {
+	if prefix == "" {
+		return handler
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path, ok := cutPrefix(r.URL.Path, prefix, fold)
+		rawPath, rawOK := cutPrefix(r.URL.RawPath, prefix, fold)
+
+		if !ok || (r.URL.RawPath != "" && !rawOK) {
+			http.NotFound(w, r)
+			return
+		}
+
+		var stripped = new(http.Request)
+		*stripped = *r
+
+		stripped.URL = new(url.URL)
+		*stripped.URL = *r.URL
+		stripped.URL.Path, stripped.URL.RawPath = path, rawPath
+
+		handler.ServeHTTP(w, stripped)
+	})
+}
+
+// cutPrefix - returns path without prefix and true if path starts with it, case is ignored if 'fold' is set.
+func cutPrefix(path, prefix string, fold bool) (string, bool) {
+	if rest, ok := strings.CutPrefix(path, prefix); ok || !fold {
+		return rest, ok
+	}
+
+	if len(path) < len(prefix) || !strings.EqualFold(path[:len(prefix)], prefix) {
+		return path, false
+	}
+
+	return path[len(prefix):], true
+}
+
+// handle - adapts http.Handler to route.
+func handle(handler http.Handler) Route {
+	return func(ctx context.Context, request Request, response Response) error {
+		handler.ServeHTTP(
+			response.ResponseWriter(),
+			request.GetRequest().WithContext(ctx),
+		)
+
+		return nil
+	}
+}
@@ -0,0 +1,218 @@
+package certs
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"fmt"
+	"math/big"
+	"net"
+	"os"
+	"sync"
+	"time"
+)
+
+const (
+	// checkInterval - minimal interval between checks of certificate files modification.
+	checkInterval = time.Second
+
+	selfSignedValidity = 365 * 24 * time.Hour
+	serialNumberBits   = 128
+)
+
+type (
+	// Reloader - provides certificate loaded from files and reloads it when files change on disk.
+	Reloader struct {
+		certFile string
+		keyFile  string
+
+		// reloading - serializes checks of files, so concurrent handshakes don't reload certificate in parallel.
+		reloading sync.Mutex
+
+		mutex   sync.RWMutex
+		cert    *tls.Certificate
+		state   [2]fileState
+		checked time.Time
+
+		onError func(error)
+	}
+
+	// fileState - modification time and size of file telling if file was replaced.
+	fileState struct {
+		modTime time.Time
+		size    int64
+	}
+)
+
+func NewReloader(certFile, keyFile string, onError func(error)) (*Reloader, error) {
+	var reloader = &Reloader{
+		certFile: certFile,
+		keyFile:  keyFile,
+		onError:  onError,
+	}
+
+	state, err := reloader.stat()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := reloader.load(state); err != nil {
+		return nil, err
+	}
+
+	return reloader, nil
+}
+
+// GetCertificate - returns actual certificate, can be used as 'tls.Config.GetCertificate'.
+func (r *Reloader) GetCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	r.mutex.RLock()
+	var cert, needCheck = r.cert, time.Since(r.checked) > checkInterval
+	r.mutex.RUnlock()
+
+	if !needCheck {
+		return cert, nil
+	}
+
+	r.reloading.Lock()
+	defer r.reloading.Unlock()
+
+	// Files could be checked by another handshake while waiting.
+	r.mutex.RLock()
+	needCheck = time.Since(r.checked) > checkInterval
+	r.mutex.RUnlock()
+
+	if needCheck {
+		if err := r.reload(); err != nil && r.onError != nil {
+			r.onError(err)
+		}
+	}
+
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	return r.cert, nil
+}
+
+// reload - loads certificate if modification time or size of any file differs from loaded ones,
+// so replacement keeping older modification time (e.g. 'cp -p') is reloaded too.
+func (r *Reloader) reload() error {
+	r.mutex.Lock()
+	r.checked = time.Now()
+	r.mutex.Unlock()
+
+	state, err := r.stat()
+	if err != nil {
+		return err
+	}
+
+	r.mutex.RLock()
+	var changed = !state[0].equal(r.state[0]) || !state[1].equal(r.state[1])
+	r.mutex.RUnlock()
+
+	if !changed {
+		return nil
+	}
+
+	return r.load(state)
+}
+
+func (r *Reloader) load(state [2]fileState) error {
+	cert, err := tls.LoadX509KeyPair(r.certFile, r.keyFile)
+	if err != nil {
+		return fmt.Errorf("loading certificate failed: %w", err)
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.cert = &cert
+	r.state = state
+	r.checked = time.Now()
+
+	return nil
+}
+
+// stat - returns states of certificate and key files.
+func (r *Reloader) stat() ([2]fileState, error) {
+	var state [2]fileState
+
+	for i, file := range []string{r.certFile, r.keyFile} {
+		info, err := os.Stat(file)
+		if err != nil {
+			return state, fmt.Errorf("checking certificate file failed: %w", err)
+		}
+
+		state[i] = fileState{modTime: info.ModTime(), size: info.Size()}
+	}
+
+	return state, nil
+}
+
+func (state fileState) equal(other fileState) bool {
+	return state.modTime.Equal(other.modTime) && state.size == other.size
+}
+
+// SelfSigned - generates in-memory self-signed certificate for provided hosts (ip addresses or dns names).
+// Intended for development only.
+func SelfSigned(hosts ...string) (tls.Certificate, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return tls.Certificate{}, fmt.Errorf("generating key failed: %w", err)
+	}
+
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), serialNumberBits))
+	if err != nil {
+		return tls.Certificate{}, fmt.Errorf("generating serial number failed: %w", err)
+	}
+
+	if len(hosts) == 0 {
+		hosts = []string{"localhost", "127.0.0.1", "::1"}
+	}
+
+	var template = x509.Certificate{
+		SerialNumber:          serial,
+		Subject:               pkix.Name{Organization: []string{"engi self-signed"}},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(selfSignedValidity),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+
+	for _, host := range hosts {
+		if ip := net.ParseIP(host); ip != nil {
+			template.IPAddresses = append(template.IPAddresses, ip)
+		} else {
+			template.DNSNames = append(template.DNSNames, host)
+		}
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, &template, &template, &key.PublicKey, key)
+	if err != nil {
+		return tls.Certificate{}, fmt.Errorf("creating certificate failed: %w", err)
+	}
+
+	return tls.Certificate{
+		Certificate: [][]byte{der},
+		PrivateKey:  key,
+	}, nil
+}
+
+// CertPool - reads PEM encoded certificates bundle from file.
+func CertPool(file string) (*x509.CertPool, error) {
+	bytes, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("reading CA bundle failed: %w", err)
+	}
+
+	var pool = x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(bytes) {
+		return nil, fmt.Errorf("no certificates found in '%s'", file)
+	}
+
+	return pool, nil
+}
@@ -0,0 +1,242 @@
+package certs
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"net"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+// writeCert - generates self-signed certificate for host and writes it with its key into PEM files.
+func writeCert(t *testing.T, host, certFile, keyFile string, modTime time.Time) {
+	t.Helper()
+
+	cert, err := SelfSigned(host)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	key, err := x509.MarshalPKCS8PrivateKey(cert.PrivateKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for file, block := range map[string]*pem.Block{
+		certFile: {Type: "CERTIFICATE", Bytes: cert.Certificate[0]},
+		keyFile:  {Type: "PRIVATE KEY", Bytes: key},
+	} {
+		if err := os.WriteFile(file, pem.EncodeToMemory(block), 0o600); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := os.Chtimes(file, modTime, modTime); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+// host - returns the first DNS name of certificate.
+func host(t *testing.T, cert *tls.Certificate) string {
+	t.Helper()
+
+	parsed, err := x509.ParseCertificate(cert.Certificate[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return parsed.DNSNames[0]
+}
+
+// expire - makes reloader check files on next handshake.
+func (r *Reloader) expire() {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.checked = time.Time{}
+}
+
+func TestReloader(t *testing.T) {
+	var (
+		dir      = t.TempDir()
+		certFile = filepath.Join(dir, "cert.pem")
+		keyFile  = filepath.Join(dir, "key.pem")
+		modTime  = time.Now().Add(-time.Hour)
+	)
+
+	writeCert(t, "first.example", certFile, keyFile, modTime)
+
+	reloader, err := NewReloader(certFile, keyFile, func(err error) { t.Error(err) })
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	first, _ := reloader.GetCertificate(nil)
+	if name := host(t, first); name != "first.example" {
+		t.Fatalf("expected 'first.example', got '%s'", name)
+	}
+
+	reloader.expire()
+
+	if cert, _ := reloader.GetCertificate(nil); cert != first {
+		t.Fatal("unchanged certificate was reloaded")
+	}
+
+	// Replacement restored with older modification time.
+	writeCert(t, "second.example", certFile, keyFile, modTime.Add(-time.Hour))
+
+	if cert, _ := reloader.GetCertificate(nil); cert != first {
+		t.Fatal("certificate was reloaded before check interval passed")
+	}
+
+	reloader.expire()
+
+	if cert, _ := reloader.GetCertificate(nil); host(t, cert) != "second.example" {
+		t.Fatalf("expected 'second.example', got '%s'", host(t, cert))
+	}
+}
+
+func TestReloaderReloadsOnce(t *testing.T) {
+	var (
+		dir      = t.TempDir()
+		certFile = filepath.Join(dir, "cert.pem")
+		keyFile  = filepath.Join(dir, "key.pem")
+	)
+
+	writeCert(t, "first.example", certFile, keyFile, time.Now().Add(-time.Hour))
+
+	reloader, err := NewReloader(certFile, keyFile, func(err error) { t.Error(err) })
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	writeCert(t, "second.example", certFile, keyFile, time.Now())
+	reloader.expire()
+
+	var (
+		wg    sync.WaitGroup
+		certs = make([]*tls.Certificate, 16)
+	)
+
+	for i := range certs {
+		wg.Add(1)
+
+		go func(i int) {
+			defer wg.Done()
+
+			certs[i], _ = reloader.GetCertificate(nil)
+		}(i)
+	}
+
+	wg.Wait()
+
+	for _, cert := range certs {
+		if cert != certs[0] || host(t, cert) != "second.example" {
+			t.Fatal("certificate was reloaded by concurrent handshakes in parallel")
+		}
+	}
+}
+
+func TestReloaderKeepsCertificateOnError(t *testing.T) {
+	var (
+		dir      = t.TempDir()
+		certFile = filepath.Join(dir, "cert.pem")
+		keyFile  = filepath.Join(dir, "key.pem")
+		errs     = make([]error, 0)
+	)
+
+	writeCert(t, "first.example", certFile, keyFile, time.Now().Add(-time.Hour))
+
+	reloader, err := NewReloader(certFile, keyFile, func(err error) { errs = append(errs, err) })
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := os.WriteFile(keyFile, []byte("broken"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	reloader.expire()
+
+	if cert, _ := reloader.GetCertificate(nil); host(t, cert) != "first.example" {
+		t.Fatalf("expected 'first.example', got '%s'", host(t, cert))
+	}
+
+	if len(errs) != 1 {
+		t.Fatalf("expected 1 error, got %v", errs)
+	}
+}
+
+func TestNewReloaderFails(t *testing.T) {
+	var dir = t.TempDir()
+
+	if _, err := NewReloader(filepath.Join(dir, "cert.pem"), filepath.Join(dir, "key.pem"), nil); err == nil {
+		t.Fatal("expected error for missing files")
+	}
+}
+
+func TestSelfSigned(t *testing.T) {
+	for _, test := range []struct {
+		hosts []string
+		dns   []string
+		ips   []string
+	}{
+		{nil, []string{"localhost"}, []string{"127.0.0.1", "::1"}},
+		{[]string{"api.example", "10.0.0.1"}, []string{"api.example"}, []string{"10.0.0.1"}},
+	} {
+		cert, err := SelfSigned(test.hosts...)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		parsed, err := x509.ParseCertificate(cert.Certificate[0])
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		for _, name := range test.dns {
+			if err := parsed.VerifyHostname(name); err != nil {
+				t.Fatalf("%v: %s", test.hosts, err)
+			}
+		}
+
+		for _, ip := range test.ips {
+			if err := parsed.VerifyHostname(net.ParseIP(ip).String()); err != nil {
+				t.Fatalf("%v: %s", test.hosts, err)
+			}
+		}
+
+		if len(parsed.DNSNames) != len(test.dns) || len(parsed.IPAddresses) != len(test.ips) {
+			t.Fatalf("%v: expected names %v and ips %v, got %v and %v",
+				test.hosts, test.dns, test.ips, parsed.DNSNames, parsed.IPAddresses)
+		}
+	}
+}
+
+func TestCertPool(t *testing.T) {
+	var (
+		dir      = t.TempDir()
+		certFile = filepath.Join(dir, "ca.pem")
+		empty    = filepath.Join(dir, "empty.pem")
+	)
+
+	writeCert(t, "ca.example", certFile, filepath.Join(dir, "key.pem"), time.Now())
+
+	if err := os.WriteFile(empty, nil, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := CertPool(certFile); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, file := range []string{empty, filepath.Join(dir, "missing.pem")} {
+		if _, err := CertPool(file); err == nil {
+			t.Fatalf("%s: expected error", file)
+		}
+	}
+}
@@ -0,0 +1,132 @@
+package listen
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"syscall"
+)
+
+const (
+	// listenFDsStart - first file descriptor passed by socket activation (SD_LISTEN_FDS_START).
+	listenFDsStart = 3
+
+	envListenPID     = "LISTEN_PID"
+	envListenFDs     = "LISTEN_FDS"
+	envListenFDNames = "LISTEN_FDNAMES"
+)
+
+var (
+	ErrNoActivatedSockets = errors.New("no sockets passed by socket activation")
+	ErrSocketInUse        = errors.New("socket is in use")
+)
+
+// TCP - listens on TCP network address.
+func TCP(address string) ([]net.Listener, error) {
+	listener, err := net.Listen("tcp", address)
+	if err != nil {
+		return nil, err
+	}
+
+	return []net.Listener{listener}, nil
+}
+
+// Unix - listens on Unix domain socket, removing stale socket file if it exists.
+// Socket is stale if nobody accepts connections on it, socket of running server is never removed.
+func Unix(path string) ([]net.Listener, error) {
+	if info, err := os.Stat(path); err == nil {
+		if info.Mode().Type() != fs.ModeSocket {
+			return nil, fmt.Errorf("'%s' exists and is not a socket", path)
+		}
+
+		conn, err := net.Dial("unix", path)
+		if err == nil {
+			conn.Close()
+
+			return nil, fmt.Errorf("%w: '%s'", ErrSocketInUse, path)
+		}
+
+		if !errors.Is(err, syscall.ECONNREFUSED) {
+			return nil, fmt.Errorf("checking socket failed: %w", err)
+		}
+
+		if err := os.Remove(path); err != nil {
+			return nil, fmt.Errorf("removing stale socket failed: %w", err)
+		}
+	}
+
+	listener, err := net.Listen("unix", path)
+	if err != nil {
+		return nil, err
+	}
+
+	return []net.Listener{listener}, nil
+}
+
+// Activated - returns listeners from file descriptors passed using systemd-style socket activation
+// ('LISTEN_PID', 'LISTEN_FDS' and 'LISTEN_FDNAMES' environment variables).
+// If names are provided only sockets with these names are returned, so sockets can be split between listeners.
+func Activated(names ...string) ([]net.Listener, error) {
+	if pid := os.Getenv(envListenPID); pid != "" && pid != strconv.Itoa(os.Getpid()) {
+		return nil, ErrNoActivatedSockets
+	}
+
+	count, err := strconv.Atoi(os.Getenv(envListenFDs))
+	if err != nil || count <= 0 {
+		return nil, ErrNoActivatedSockets
+	}
+
+	var fdNames = strings.Split(os.Getenv(envListenFDNames), ":")
+
+	var listeners = make([]net.Listener, 0, count)
+
+	for i := 0; i < count; i++ {
+		var name = fmt.Sprintf("LISTEN_FD_%d", listenFDsStart+i)
+		if i < len(fdNames) && fdNames[i] != "" {
+			name = fdNames[i]
+		}
+
+		if len(names) != 0 && !contains(names, name) {
+			continue
+		}
+
+		var file = os.NewFile(uintptr(listenFDsStart+i), name)
+
+		listener, err := net.FileListener(file)
+		file.Close()
+
+		if err != nil {
+			Close(listeners)
+			return nil, fmt.Errorf("socket '%s': %w", name, err)
+		}
+
+		listeners = append(listeners, listener)
+	}
+
+	if len(listeners) == 0 {
+		return nil, ErrNoActivatedSockets
+	}
+
+	return listeners, nil
+}
+
+// Close - closes all listeners ignoring errors.
+func Close(listeners []net.Listener) {
+	for _, listener := range listeners {
+		listener.Close()
+	}
+}
+
+func contains(slice []string, item string) bool {
+	for _, i := range slice {
+		if i == item {
+			return true
+		}
+	}
+
+	return false
+}
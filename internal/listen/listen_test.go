@@ -0,0 +1,67 @@
+package listen
+
+import (
+	"errors"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestUnixRemovesStaleSocket(t *testing.T) {
+	var path = filepath.Join(t.TempDir(), "engi.sock")
+
+	stale, err := net.Listen("unix", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Socket file is left on disk as if process was killed.
+	stale.(*net.UnixListener).SetUnlinkOnClose(false)
+	stale.Close()
+
+	listeners, err := Unix(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	Close(listeners)
+}
+
+func TestUnixKeepsSocketInUse(t *testing.T) {
+	var path = filepath.Join(t.TempDir(), "engi.sock")
+
+	running, err := Unix(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer Close(running)
+
+	if _, err := Unix(path); !errors.Is(err, ErrSocketInUse) {
+		t.Fatalf("expected '%s', got '%v'", ErrSocketInUse, err)
+	}
+
+	conn, err := net.Dial("unix", path)
+	if err != nil {
+		t.Fatalf("running server lost its socket: %s", err)
+	}
+
+	conn.Close()
+}
+
+func TestUnixKeepsOtherFiles(t *testing.T) {
+	var path = filepath.Join(t.TempDir(), "engi.sock")
+
+	if err := os.WriteFile(path, nil, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := Unix(path); err == nil {
+		t.Fatal("expected error for regular file")
+	}
+
+	if _, err := os.Stat(path); err != nil {
+		t.Fatalf("file was removed: %s", err)
+	}
+}
@@ -56,7 +56,7 @@ func Bearer(isValid func(string) bool) request.Middleware {
 			return response.AsError(http.StatusUnauthorized, unathorizedResponse)
 		}
 
-		if isValid(strings.TrimPrefix(authHeader, bearerPrefix)) {
+		if !strings.HasPrefix(header, bearerPrefix) || !isValid(strings.TrimPrefix(header, bearerPrefix)) {
 			return response.AsError(http.StatusUnauthorized, unathorizedResponse)
 		}
 
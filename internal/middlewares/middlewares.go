@@ -2,7 +2,9 @@ package middlewares
 
 import (
 	"net/http"
+	"time"
 
+	"github.com/KlyuchnikovV/engi/internal/pathfinder"
 	"github.com/KlyuchnikovV/engi/internal/request"
 	"github.com/KlyuchnikovV/engi/response"
 )
@@ -13,11 +15,28 @@ func noOpMiddleware(*request.Request, http.ResponseWriter) *response.AsObject {
 
 type Register func(middlewares *Middlewares)
 
+// Timeout - route's deadline settings.
+type Timeout struct {
+	// Duration - default deadline of route.
+	Duration time.Duration
+	// Code - http code responded when deadline exceeded.
+	Code int
+	// Header - name of header client can set own timeout with.
+	Header string
+	// Max - maximal timeout client can set with header.
+	Max time.Duration
+}
+
 type Middlewares struct {
-	cors   request.Middleware
-	auth   request.Middleware
-	params []request.Middleware
-	other  []request.Middleware
+	cors    request.Middleware
+	auth    request.Middleware
+	params  []request.Middleware
+	other   []request.Middleware
+	timeout *Timeout
+
+	name       string
+	links      []string
+	conditions []pathfinder.Condition
 }
 
 func New(registrators ...Register) *Middlewares {
@@ -65,6 +84,50 @@ func (m *Middlewares) AddOther(middlewares ...request.Middleware) {
 	m.other = append(m.other, middlewares...)
 }
 
+func (m *Middlewares) SetTimeout(timeout Timeout) {
+	m.timeout = &timeout
+}
+
+// Timeout - returns route's timeout settings or nil if route has no timeout.
+func (m *Middlewares) Timeout() *Timeout {
+	return m.timeout
+}
+
+// SetName - sets name route can be referenced by.
+func (m *Middlewares) SetName(name string) {
+	m.name = name
+}
+
+// Name - returns route's name or empty string if route is unnamed.
+func (m *Middlewares) Name() string {
+	return m.name
+}
+
+// AddLinks - adds names of routes the route builds URLs for.
+func (m *Middlewares) AddLinks(names ...string) {
+	m.links = append(m.links, names...)
+}
+
+// Links - returns names of routes the route builds URLs for.
+func (m *Middlewares) Links() []string {
+	return m.links
+}
+
+// AddConditions - adds conditions request has to satisfy to be routed to route (e.g. host or header).
+func (m *Middlewares) AddConditions(conditions ...pathfinder.Condition) {
+	m.conditions = append(m.conditions, conditions...)
+}
+
+// Conditions - returns conditions request has to satisfy to be routed to route.
+func (m *Middlewares) Conditions() []pathfinder.Condition {
+	return m.conditions
+}
+
+// HandleCORS - calls only CORS middleware, e.g. for automatic OPTIONS responses.
+func (m *Middlewares) HandleCORS(r *request.Request, w http.ResponseWriter) *response.AsObject {
+	return m.cors(r, w)
+}
+
 func (m *Middlewares) Handle(r *request.Request, w http.ResponseWriter) *response.AsObject {
 	if err := m.cors(r, w); err != nil {
 		return err
@@ -0,0 +1,213 @@
+package pathfinder
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"slices"
+	"strings"
+)
+
+// Condition - requirement to request besides its path, e.g. host or header.
+// Route is matched only if all its conditions are satisfied.
+type Condition interface {
+	// Key - identifies condition, routes with same path and conditions are duplicates.
+	Key() string
+	// Match - checks request returning values captured from it.
+	Match(r *http.Request) (map[string]string, bool)
+	// Specificity - routes with more specific conditions are tried first.
+	Specificity() int
+}
+
+// conditionsKey - returns key identifying set of conditions regardless of their order.
+func conditionsKey(conditions []Condition) string {
+	var keys = make([]string, 0, len(conditions))
+
+	for _, condition := range conditions {
+		keys = append(keys, condition.Key())
+	}
+
+	slices.Sort(keys)
+
+	return strings.Join(keys, " ")
+}
+
+// Specificity - returns total specificity of conditions.
+func Specificity(conditions []Condition) int {
+	var result int
+
+	for _, condition := range conditions {
+		result += condition.Specificity()
+	}
+
+	return result
+}
+
+// matchAll - checks all conditions returning values captured by them.
+func matchAll(r *http.Request, conditions []Condition) (map[string]string, bool) {
+	var captures map[string]string
+
+	for _, condition := range conditions {
+		values, ok := condition.Match(r)
+		if !ok {
+			return nil, false
+		}
+
+		for key, value := range values {
+			if captures == nil {
+				captures = make(map[string]string, len(values))
+			}
+
+			captures[key] = value
+		}
+	}
+
+	return captures, true
+}
+
+type (
+	// host - matches request's host with one of patterns.
+	host struct {
+		patterns [][]string
+	}
+
+	// header - matches request's header with one of values or its presence.
+	header struct {
+		name   string
+		values []string
+	}
+)
+
+// Host - creates condition matching request's host (port is ignored) with one of patterns.
+// Pattern's labels are matched case-insensitively, '*' matches any label
+// and '{name}' matches any label capturing it ('{tenant}.example.com').
+func Host(patterns ...string) Condition {
+	var result = host{patterns: make([][]string, 0, len(patterns))}
+
+	for _, pattern := range patterns {
+		result.patterns = append(result.patterns, strings.Split(strings.ToLower(pattern), "."))
+	}
+
+	return result
+}
+
+func (h host) Key() string {
+	var patterns = make([]string, 0, len(h.patterns))
+
+	for _, pattern := range h.patterns {
+		patterns = append(patterns, strings.Join(pattern, "."))
+	}
+
+	slices.Sort(patterns)
+
+	return fmt.Sprintf("host(%s)", strings.Join(patterns, ","))
+}
+
+// Specificity - returns number of literal labels of the least specific pattern.
+func (h host) Specificity() int {
+	var result = -1
+
+	for _, pattern := range h.patterns {
+		var literal int
+
+		for _, label := range pattern {
+			if label != "*" && !strings.HasPrefix(label, "{") {
+				literal++
+			}
+		}
+
+		if result < 0 || literal < result {
+			result = literal
+		}
+	}
+
+	return max(result, 0)
+}
+
+func (h host) Match(r *http.Request) (map[string]string, bool) {
+	var name = r.Host
+	if hostname, _, err := net.SplitHostPort(name); err == nil {
+		name = hostname
+	}
+
+	var labels = strings.Split(strings.TrimSuffix(strings.ToLower(name), "."), ".")
+
+	for _, pattern := range h.patterns {
+		if captures, ok := matchLabels(pattern, labels); ok {
+			return captures, true
+		}
+	}
+
+	return nil, false
+}
+
+// matchLabels - matches host labels with pattern ones.
+func matchLabels(pattern, labels []string) (map[string]string, bool) {
+	if len(pattern) != len(labels) {
+		return nil, false
+	}
+
+	var captures map[string]string
+
+	for i, label := range pattern {
+		switch {
+		case labels[i] == "":
+			return nil, false
+		case label == "*":
+		case strings.HasPrefix(label, "{") && strings.HasSuffix(label, "}"):
+			if captures == nil {
+				captures = make(map[string]string)
+			}
+
+			captures[label[1:len(label)-1]] = labels[i]
+		case label != labels[i]:
+			return nil, false
+		}
+	}
+
+	return captures, true
+}
+
+// Header - creates condition matching request's header with one of values or, if no values passed, its presence.
+func Header(name string, values ...string) Condition {
+	return header{
+		name:   http.CanonicalHeaderKey(name),
+		values: values,
+	}
+}
+
+func (h header) Key() string {
+	var values = slices.Clone(h.values)
+
+	slices.Sort(values)
+
+	return fmt.Sprintf("header(%s=%s)", h.name, strings.Join(values, ","))
+}
+
+// Specificity - header with values is more specific than header's presence.
+func (h header) Specificity() int {
+	if len(h.values) == 0 {
+		return 0
+	}
+
+	return 1
+}
+
+func (h header) Match(r *http.Request) (map[string]string, bool) {
+	var values = r.Header.Values(h.name)
+	if len(values) == 0 {
+		return nil, false
+	}
+
+	if len(h.values) == 0 {
+		return nil, true
+	}
+
+	for _, value := range values {
+		if slices.Contains(h.values, value) {
+			return nil, true
+		}
+	}
+
+	return nil, false
+}
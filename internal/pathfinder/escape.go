@@ -0,0 +1,68 @@
+package pathfinder
+
+import (
+	"strings"
+)
+
+const upperHex = "0123456789ABCDEF"
+
+var unescaper = strings.NewReplacer("%2F", "/", "%25", "%")
+
+// NormalizeEscaped - decodes escaped path ('url.URL.EscapedPath') except for '%2F' and '%25',
+// so encoded slashes don't split segments and decoded path can be split by '/' unambiguously.
+// Invalid escapes are escaped as '%25'.
+func NormalizeEscaped(escaped string) string {
+	if !strings.Contains(escaped, "%") {
+		return escaped
+	}
+
+	var result strings.Builder
+
+	result.Grow(len(escaped))
+
+	for i := 0; i < len(escaped); i++ {
+		if escaped[i] != '%' {
+			result.WriteByte(escaped[i])
+			continue
+		}
+
+		if i+2 >= len(escaped) || !isHex(escaped[i+1]) || !isHex(escaped[i+2]) {
+			result.WriteString("%25")
+			continue
+		}
+
+		var b = unhex(escaped[i+1])<<4 | unhex(escaped[i+2])
+
+		if b == '/' || b == '%' {
+			result.WriteByte('%')
+			result.WriteByte(upperHex[b>>4])
+			result.WriteByte(upperHex[b&15])
+		} else {
+			result.WriteByte(b)
+		}
+
+		i += 2
+	}
+
+	return result.String()
+}
+
+// unescape - decodes segment of path normalized by NormalizeEscaped.
+func unescape(segment string) string {
+	if !strings.Contains(segment, "%") {
+		return segment
+	}
+
+	return unescaper.Replace(segment)
+}
+
+func unhex(b byte) byte {
+	switch {
+	case '0' <= b && b <= '9':
+		return b - '0'
+	case 'a' <= b && b <= 'f':
+		return b - 'a' + 10
+	default:
+		return b - 'A' + 10
+	}
+}
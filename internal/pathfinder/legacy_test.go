@@ -0,0 +1,176 @@
+package pathfinder
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/KlyuchnikovV/engi/internal/request"
+)
+
+// Implementation of path finder replaced by radix tree, kept to benchmark against it.
+
+var (
+	legacyParameterRegexp = regexp.MustCompile("{[a-zA-Z]*}")
+	legacyValueRegexp     = regexp.MustCompile("^[^/]+")
+)
+
+type legacyFinder struct {
+	exactHandlers  map[string]Handler
+	regexpHandlers []legacyNode
+}
+
+func newLegacyFinder() *legacyFinder {
+	return &legacyFinder{
+		exactHandlers:  make(map[string]Handler),
+		regexpHandlers: make([]legacyNode, 0),
+	}
+}
+
+func (finder *legacyFinder) Add(path string, handler Handler) {
+	if !legacyParameterRegexp.MatchString(path) {
+		finder.exactHandlers[path] = handler
+		return
+	}
+
+	var (
+		parts = strings.Split(path, "/")
+		tree  = newLegacyNode(parts[0], nil)
+	)
+
+	tree.Add(handler, parts[1:]...)
+
+	finder.regexpHandlers = append(finder.regexpHandlers, tree)
+}
+
+func (finder *legacyFinder) Handle(request *request.Request, uri string) Handler {
+	handler, ok := finder.exactHandlers[uri]
+	if ok {
+		return handler
+	}
+
+	for _, regexpHandler := range finder.regexpHandlers {
+		if handler := regexpHandler.GetHandler(request, uri); handler != nil {
+			return handler
+		}
+	}
+
+	return nil
+}
+
+type legacyNode interface {
+	Add(handler Handler, parts ...string)
+	GetHandler(request *request.Request, path string) Handler
+	Equal(node legacyNode) bool
+}
+
+func newLegacyNode(parameter string, handler Handler) legacyNode {
+	if legacyParameterRegexp.MatchString(parameter) {
+		return &legacyRegexpNode{
+			name:    strings.Trim(parameter, "{}"),
+			pattern: *legacyValueRegexp,
+			Handler: handler,
+		}
+	}
+
+	return &legacyStringNode{pattern: parameter, Handler: handler}
+}
+
+type legacyStringNode struct {
+	pattern string
+	nodes   []legacyNode
+	Handler
+}
+
+func (s *legacyStringNode) Add(handler Handler, parts ...string) {
+	s.nodes = legacyAdd(s.nodes, handler, parts...)
+}
+
+func (s *legacyStringNode) GetHandler(request *request.Request, path string) Handler {
+	path = strings.TrimLeft(path, "/")
+
+	if !strings.HasPrefix(path, s.pattern) {
+		return nil
+	}
+
+	var subPath, _ = strings.CutPrefix(path, s.pattern)
+
+	if len(subPath) == 0 {
+		return s.Handler
+	}
+
+	for _, node := range s.nodes {
+		if handler := node.GetHandler(request, subPath); handler != nil {
+			return handler
+		}
+	}
+
+	return nil
+}
+
+func (s *legacyStringNode) Equal(n legacyNode) bool {
+	other, ok := n.(*legacyStringNode)
+
+	return ok && s.pattern == other.pattern
+}
+
+type legacyRegexpNode struct {
+	name    string
+	pattern regexp.Regexp
+	nodes   []legacyNode
+	Handler
+}
+
+func (r *legacyRegexpNode) Add(handler Handler, parts ...string) {
+	r.nodes = legacyAdd(r.nodes, handler, parts...)
+}
+
+func (r *legacyRegexpNode) GetHandler(request *request.Request, path string) Handler {
+	path = strings.TrimLeft(path, "/")
+
+	var parts = strings.Split(path, "/")
+	if len(parts) == 0 {
+		return nil
+	}
+
+	if !r.pattern.MatchString(parts[0]) {
+		return nil
+	}
+
+	request.AddInPathParameter(r.name, parts[0], parts[0])
+
+	if len(parts) == 1 {
+		return r.Handler
+	}
+
+	for _, node := range r.nodes {
+		if handler := node.GetHandler(request, parts[1]); handler != nil {
+			return handler
+		}
+	}
+
+	return nil
+}
+
+func (r *legacyRegexpNode) Equal(n legacyNode) bool {
+	other, ok := n.(*legacyRegexpNode)
+
+	return ok && r.name == other.name
+}
+
+func legacyAdd(nodes []legacyNode, handler Handler, parts ...string) []legacyNode {
+	if len(parts) == 0 {
+		return nodes
+	}
+
+	var newNode = newLegacyNode(parts[0], handler)
+	for _, node := range nodes {
+		if node.Equal(newNode) {
+			node.Add(handler, parts[1:]...)
+			return nodes
+		}
+	}
+
+	newNode.Add(handler, parts[1:]...)
+
+	return append(nodes, newNode)
+}
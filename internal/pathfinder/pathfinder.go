@@ -2,6 +2,9 @@ package pathfinder
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"net/http"
 	"regexp"
 	"strings"
 
@@ -9,52 +12,254 @@ import (
 	"github.com/KlyuchnikovV/engi/internal/response"
 )
 
-var parameterRegexp = regexp.MustCompile("{[a-zA-Z]*}")
+var (
+	ErrAlreadyRegistered = errors.New("route already registered")
+	ErrAmbiguousRoute    = errors.New("route is ambiguous")
+	ErrInvalidPattern    = errors.New("invalid route pattern")
+
+	parameterNameRegexp = regexp.MustCompile("^[a-zA-Z_][a-zA-Z0-9_]*$")
+)
 
 type Handler func(ctx context.Context, request *request.Request, response *response.Response) error
 
+// PathFinder - finds handlers by path using compressed radix tree.
+//
+// Parameters can be constrained with type or regular expression taking part in matching:
+// '{id:int}', '{price:float}', '{flag:bool}', '{uuid:uuid}', '{date:date}' (YYYY-MM-DD) or '{slug:[a-z0-9-]+}'.
+// Value of typed parameter is converted and can be obtained without declaring path parameter for route.
+//
+// Path segments are matched with following precedence:
+//   - static segment ('get');
+//   - typed parameter ('{id:int}'), types matching same segment are tried in order int, uuid, date, bool, float;
+//   - regexp constrained parameter ('{slug:[a-z]+}'), expressions are tried in lexical order;
+//   - parameter ('{id}');
+//   - catch-all parameter ('{path...}').
+//
+// If matching by segment with higher precedence fails on further segments, segments with lower precedence are tried.
+// Lookup doesn't use regular expressions (except for regexp constrained parameters)
+// and takes time proportional to path length.
 type PathFinder struct {
-	exactHandlers  map[string]Handler
-	regexpHandlers []HanlderNode
+	root *node
+	fold bool
+}
+
+// Option - configures path finder.
+type Option func(*PathFinder)
+
+// IgnoreCase - tells path finder to match static parts of paths case-insensitively.
+func IgnoreCase(finder *PathFinder) {
+	finder.fold = true
 }
 
-func NewPathFinder() *PathFinder {
-	return &PathFinder{
-		exactHandlers:  make(map[string]Handler),
-		regexpHandlers: make([]HanlderNode, 0),
+func NewPathFinder(options ...Option) *PathFinder {
+	var finder = &PathFinder{
+		root: new(node),
 	}
+
+	for _, option := range options {
+		option(finder)
+	}
+
+	return finder
 }
 
-func (finder *PathFinder) Add(path string, handler Handler) {
-	if !parameterRegexp.MatchString(path) {
-		finder.exactHandlers[path] = handler
-		return
+// Add - registers handler for path pattern and conditions request has to satisfy (e.g. host or header).
+// Patterns matching same paths (e.g. 'get/{id}' and 'get/{name}') with same conditions are rejected.
+// Routes with conditions are tried before unconditional route of the same path.
+//
+// Catch-all parameter ('files/{path...}') matches the rest of path and must be the last segment.
+// Trailing parameters can be optional ('docs/{page?}' matches both 'docs' and 'docs/intro').
+func (finder *PathFinder) Add(path string, handler Handler, conditions ...Condition) error {
+	tokens, err := parse(path)
+	if err != nil {
+		return err
 	}
 
-	var (
-		parts = strings.Split(path, "/")
-		tree  = NewHandlerNode(parts[0], nil)
-	)
+	for i := range tokens {
+		// Paths are matched in escaped form having only '%' and '/' escaped (see NormalizeEscaped).
+		tokens[i].static = strings.ReplaceAll(tokens[i].static, "%", "%25")
+
+		if finder.fold {
+			tokens[i].static = strings.ToLower(tokens[i].static)
+		}
+	}
+
+	for _, variant := range variants(tokens) {
+		if err := finder.root.insert(variant, handler, conditions); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Match - checks if handler for request with uri exists.
+// Uri is expected in escaped form returned by NormalizeEscaped.
+func (finder *PathFinder) Match(r *http.Request, uri string) bool {
+	return finder.root.find(uri, r, &match{fold: finder.fold})
+}
 
-	tree.Add(handler, parts[1:]...)
+// KeepsSlash - checks if uri is matched by catch-all parameter or is the root of catch-all route
+// ('files' for 'files/{path...}'), so trailing slash of request's path is meaningful for its handler.
+func (finder *PathFinder) KeepsSlash(r *http.Request, uri string) bool {
+	var found = match{fold: finder.fold}
 
-	finder.regexpHandlers = append(finder.regexpHandlers, tree)
+	return finder.root.find(uri, r, &found) && found.tree
 }
 
+// Handle - returns handler for uri and saves path parameters and values captured by conditions into request
+// or nil if path not found. Uri is expected in escaped form returned by NormalizeEscaped,
+// so encoded slashes are kept inside of parameters ('files/a%2Fb' gives 'a/b' for 'files/{name}').
 func (finder *PathFinder) Handle(
 	request *request.Request,
 	uri string,
 ) Handler {
-	handler, ok := finder.exactHandlers[uri]
-	if ok {
-		return handler
+	var found = match{fold: finder.fold}
+
+	if !finder.root.find(uri, request.GetRequest(), &found) {
+		return nil
+	}
+
+	for _, param := range found.params {
+		request.AddInPathParameter(param.name, param.value, param.parsed)
+	}
+
+	for key, value := range found.captures {
+		request.AddInHostParameter(key, value)
+	}
+
+	return found.handler
+}
+
+type (
+	// token - part of route pattern, either static string or parameter.
+	token struct {
+		static   string
+		param    string
+		typ      paramType
+		catchAll bool
+		optional bool
 	}
 
-	for _, regexpHandler := range finder.regexpHandlers {
-		if handler := regexpHandler.GetHandler(request, uri); handler != nil {
-			return handler
+	// param - matched path parameter.
+	param struct {
+		name   string
+		value  string
+		parsed interface{}
+	}
+)
+
+// parse - splits pattern into static parts and parameters occupying whole segments.
+// Segment can't contain '/', so neither can regexp constraint.
+func parse(path string) ([]token, error) {
+	var (
+		tokens = make([]token, 0)
+		static strings.Builder
+	)
+
+	for i, segment := range strings.Split(path, "/") {
+		if i != 0 {
+			static.WriteByte('/')
+		}
+
+		if !strings.ContainsAny(segment, "{}") {
+			static.WriteString(segment)
+			continue
+		}
+
+		if !strings.HasPrefix(segment, "{") || !strings.HasSuffix(segment, "}") {
+			return nil, fmt.Errorf("%w: parameter should occupy whole segment (got: '%s')", ErrInvalidPattern, segment)
 		}
+
+		param, err := parseParam(segment[1 : len(segment)-1])
+		if err != nil {
+			return nil, err
+		}
+
+		if static.Len() != 0 {
+			tokens = append(tokens, token{static: static.String()})
+			static.Reset()
+		}
+
+		tokens = append(tokens, param)
+	}
+
+	if static.Len() != 0 {
+		tokens = append(tokens, token{static: static.String()})
+	}
+
+	return tokens, validate(tokens)
+}
+
+// parseParam - parses parameter declaration 'name[...][?][:constraint]'.
+func parseParam(declaration string) (token, error) {
+	var (
+		name, constraint, _ = strings.Cut(declaration, ":")
+		result              token
+		err                 error
+	)
+
+	name, result.optional = strings.CutSuffix(name, "?")
+	name, result.catchAll = strings.CutSuffix(name, "...")
+
+	if !parameterNameRegexp.MatchString(name) {
+		return token{}, fmt.Errorf("%w: invalid parameter name '%s'", ErrInvalidPattern, name)
+	}
+
+	if result.catchAll && constraint != "" {
+		return token{}, fmt.Errorf("%w: catch-all parameter '%s' can't be constrained", ErrInvalidPattern, name)
+	}
+
+	result.param = name
+
+	if result.typ, err = newParamType(constraint); err != nil {
+		return token{}, err
+	}
+
+	return result, nil
+}
+
+// validate - checks that catch-all parameter is the last one and
+// optional parameters are followed only by optional parameters.
+func validate(tokens []token) error {
+	var optional bool
+
+	for i, part := range tokens {
+		if part.catchAll && i != len(tokens)-1 {
+			return fmt.Errorf("%w: catch-all parameter '%s' must be the last segment", ErrInvalidPattern, part.param)
+		}
+
+		if optional && !part.optional && part.static != "/" {
+			return fmt.Errorf("%w: only optional parameters can follow optional parameter", ErrInvalidPattern)
+		}
+
+		optional = optional || part.optional
 	}
 
 	return nil
 }
+
+// variants - expands trailing optional parameters into patterns with and without them.
+func variants(tokens []token) [][]token {
+	var result = make([][]token, 0, 1)
+
+	for i, part := range tokens {
+		if !part.optional {
+			continue
+		}
+
+		var variant = append([]token{}, tokens[:i]...)
+
+		// Separator before omitted parameter is omitted too.
+		if last := len(variant) - 1; last >= 0 && variant[last].static != "" {
+			variant[last].static = strings.TrimSuffix(variant[last].static, "/")
+			if variant[last].static == "" {
+				variant = variant[:last]
+			}
+		}
+
+		result = append(result, variant)
+	}
+
+	return append(result, tokens)
+}
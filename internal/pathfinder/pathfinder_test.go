@@ -0,0 +1,282 @@
+package pathfinder
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/KlyuchnikovV/engi/internal/request"
+	"github.com/KlyuchnikovV/engi/internal/response"
+	"github.com/KlyuchnikovV/engi/parameter/placing"
+)
+
+// named - returns handler returning its pattern as error, so matched route can be told by handler.
+func named(pattern string) Handler {
+	return func(context.Context, *request.Request, *response.Response) error {
+		return errors.New(pattern)
+	}
+}
+
+func newFinder(t testing.TB, patterns ...string) *PathFinder {
+	t.Helper()
+
+	var finder = NewPathFinder()
+
+	for _, pattern := range patterns {
+		if err := finder.Add(pattern, named(pattern)); err != nil {
+			t.Fatalf("adding '%s': %s", pattern, err)
+		}
+	}
+
+	return finder
+}
+
+// handle - returns pattern of route matched by uri and its path parameters.
+func handle(finder *PathFinder, uri string) (string, map[string]string) {
+	var (
+		req     = request.New(httptest.NewRequest("GET", "/", nil))
+		handler = finder.Handle(req, uri)
+	)
+
+	if handler == nil {
+		return "", nil
+	}
+
+	return handler(context.Background(), req, nil).Error(), req.All()[placing.InPath]
+}
+
+func TestPrecedence(t *testing.T) {
+	var finder = newFinder(t,
+		"items/{path...}",
+		"items/{name}",
+		"items/{id:int}",
+		"items/{slug:[a-z]+-[a-z]+}",
+		"items/new",
+		"{object}/{id}",
+		"get/{id}",
+	)
+
+	for _, test := range []struct {
+		uri     string
+		pattern string
+		params  map[string]string
+	}{
+		{"items/new", "items/new", map[string]string{}},
+		{"items/5", "items/{id:int}", map[string]string{"id": "5"}},
+		{"items/big-box", "items/{slug:[a-z]+-[a-z]+}", map[string]string{"slug": "big-box"}},
+		{"items/box", "items/{name}", map[string]string{"name": "box"}},
+		{"items/a/b", "items/{path...}", map[string]string{"path": "a/b"}},
+		{"get/5", "get/{id}", map[string]string{"id": "5"}},
+		{"notes/5", "{object}/{id}", map[string]string{"object": "notes", "id": "5"}},
+		{"notes", "", nil},
+	} {
+		t.Run(test.uri, func(t *testing.T) {
+			pattern, params := handle(finder, test.uri)
+
+			if pattern != test.pattern {
+				t.Fatalf("expected '%s', got '%s'", test.pattern, pattern)
+			}
+
+			if fmt.Sprint(params) != fmt.Sprint(test.params) {
+				t.Fatalf("expected parameters %v, got %v", test.params, params)
+			}
+		})
+	}
+}
+
+func TestPrecedenceDoesNotDependOnOrder(t *testing.T) {
+	var patterns = []string{"{object}/{id}", "get/{id}", "get/{id:int}", "{path...}"}
+
+	for i := range patterns {
+		var (
+			rotated = append(append([]string{}, patterns[i:]...), patterns[:i]...)
+			finder  = newFinder(t, rotated...)
+		)
+
+		for uri, expected := range map[string]string{
+			"get/5":   "get/{id:int}",
+			"get/abc": "get/{id}",
+			"notes/5": "{object}/{id}",
+			"a/b/c":   "{path...}",
+		} {
+			if pattern, _ := handle(finder, uri); pattern != expected {
+				t.Fatalf("order %v: '%s' expected '%s', got '%s'", rotated, uri, expected, pattern)
+			}
+		}
+	}
+}
+
+func TestOverlappingParametersDoNotDependOnOrder(t *testing.T) {
+	var patterns = []string{
+		"items/{price:float}",
+		"items/{flag:bool}",
+		"items/{id:int}",
+		"items/{day:date}",
+		"x/{b:[0-9a-z]+}",
+		"x/{a:[0-9]+}",
+		"x/{c:[a-z]+}",
+	}
+
+	for i := range patterns {
+		var (
+			rotated = append(append([]string{}, patterns[i:]...), patterns[:i]...)
+			finder  = newFinder(t, rotated...)
+		)
+
+		for uri, expected := range map[string]string{
+			"items/5":          "items/{id:int}",
+			"items/true":       "items/{flag:bool}",
+			"items/1.5":        "items/{price:float}",
+			"items/2024-01-02": "items/{day:date}",
+			"x/5":              "x/{a:[0-9]+}",
+			"x/5a":             "x/{b:[0-9a-z]+}",
+			"x/abc":            "x/{b:[0-9a-z]+}",
+		} {
+			if pattern, _ := handle(finder, uri); pattern != expected {
+				t.Fatalf("order %v: '%s' expected '%s', got '%s'", rotated, uri, expected, pattern)
+			}
+		}
+	}
+}
+
+func TestBacktracking(t *testing.T) {
+	var finder = newFinder(t,
+		"a/b/c",
+		"a/{x}/d",
+		"n/{id:int}/z",
+		"n/{name}/w",
+		"f/static/x",
+		"f/{path...}",
+	)
+
+	for _, test := range []struct {
+		uri     string
+		pattern string
+		params  map[string]string
+	}{
+		{"a/b/c", "a/b/c", map[string]string{}},
+		{"a/b/d", "a/{x}/d", map[string]string{"x": "b"}},
+		{"n/5/z", "n/{id:int}/z", map[string]string{"id": "5"}},
+		{"n/5/w", "n/{name}/w", map[string]string{"name": "5"}},
+		{"f/static/x", "f/static/x", map[string]string{}},
+		{"f/static/y", "f/{path...}", map[string]string{"path": "static/y"}},
+	} {
+		t.Run(test.uri, func(t *testing.T) {
+			pattern, params := handle(finder, test.uri)
+
+			if pattern != test.pattern {
+				t.Fatalf("expected '%s', got '%s'", test.pattern, pattern)
+			}
+
+			if fmt.Sprint(params) != fmt.Sprint(test.params) {
+				t.Fatalf("expected parameters %v, got %v", test.params, params)
+			}
+		})
+	}
+}
+
+func TestAmbiguousRoutes(t *testing.T) {
+	for _, test := range []struct {
+		patterns []string
+		err      error
+	}{
+		{[]string{"get/{id}", "get/{name}"}, ErrAmbiguousRoute},
+		{[]string{"get/{id:int}", "get/{n:int}"}, ErrAmbiguousRoute},
+		{[]string{"files/{path...}", "files/{rest...}"}, ErrAmbiguousRoute},
+		{[]string{"get/{id}", "get/{id}"}, ErrAlreadyRegistered},
+		{[]string{"get", "get"}, ErrAlreadyRegistered},
+		{[]string{"docs/{page?}", "docs"}, ErrAlreadyRegistered},
+		{[]string{"files/{path...}/x"}, ErrInvalidPattern},
+		{[]string{"get/id{id}"}, ErrInvalidPattern},
+	} {
+		t.Run(fmt.Sprint(test.patterns), func(t *testing.T) {
+			var (
+				finder = NewPathFinder()
+				err    error
+			)
+
+			for _, pattern := range test.patterns {
+				if err = finder.Add(pattern, named(pattern)); err != nil {
+					break
+				}
+			}
+
+			if !errors.Is(err, test.err) {
+				t.Fatalf("expected '%s', got '%v'", test.err, err)
+			}
+		})
+	}
+}
+
+func TestNotAmbiguousRoutes(t *testing.T) {
+	newFinder(t, "get/{id}", "get/{id:int}", "get/{id:uuid}", "get/{path...}", "get/static")
+}
+
+// benchmarkRoutes - routes of typical api, supported by both implementations.
+var benchmarkRoutes = []string{
+	"notes/create",
+	"notes/list",
+	"notes/get/{id}",
+	"notes/{id}/comments",
+	"notes/{id}/comments/{comment}",
+	"users/create",
+	"users/list",
+	"users/get/{id}",
+	"users/{id}/avatar",
+	"users/{id}/settings",
+	"orders/create",
+	"orders/list",
+	"orders/get/{id}",
+	"orders/{id}/items",
+	"orders/{id}/items/{item}",
+	"{object}/{id}",
+}
+
+var benchmarkPaths = map[string]string{
+	"static":       "orders/list",
+	"param":        "orders/get/42",
+	"nested param": "orders/42/items",
+	"last route":   "products/42",
+}
+
+func BenchmarkRadixTree(b *testing.B) {
+	var finder = newFinder(b, benchmarkRoutes...)
+
+	for name, uri := range benchmarkPaths {
+		b.Run(name, func(b *testing.B) {
+			var req = request.New(httptest.NewRequest("GET", "/", nil))
+
+			b.ReportAllocs()
+
+			for i := 0; i < b.N; i++ {
+				if finder.Handle(req, uri) == nil {
+					b.Fatalf("'%s' not found", uri)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkLegacy(b *testing.B) {
+	var finder = newLegacyFinder()
+
+	for _, pattern := range benchmarkRoutes {
+		finder.Add(pattern, named(pattern))
+	}
+
+	for name, uri := range benchmarkPaths {
+		b.Run(name, func(b *testing.B) {
+			var req = request.New(httptest.NewRequest("GET", "/", nil))
+
+			b.ReportAllocs()
+
+			for i := 0; i < b.N; i++ {
+				if finder.Handle(req, uri) == nil {
+					b.Fatalf("'%s' not found", uri)
+				}
+			}
+		})
+	}
+}
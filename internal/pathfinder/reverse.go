@@ -0,0 +1,66 @@
+package pathfinder
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+var (
+	ErrMissingParameter = errors.New("missing path parameter")
+	ErrInvalidParameter = errors.New("invalid path parameter")
+)
+
+// Reverse - builds escaped path from route pattern substituting parameters.
+// Values of constrained parameters are checked to match their constraints,
+// omitted optional parameters are skipped together with preceding separator.
+func Reverse(pattern string, params map[string]string) (string, error) {
+	tokens, err := parse(pattern)
+	if err != nil {
+		return "", err
+	}
+
+	var path strings.Builder
+
+	for _, part := range tokens {
+		if part.param == "" {
+			path.WriteString(escape(part.static))
+			continue
+		}
+
+		value, ok := params[part.param]
+		if !ok || (part.optional && value == "") {
+			if !part.optional {
+				return "", fmt.Errorf("%w: '%s'", ErrMissingParameter, part.param)
+			}
+
+			// The rest of parameters are optional too.
+			return strings.TrimSuffix(path.String(), "/"), nil
+		}
+
+		if part.catchAll {
+			path.WriteString(escape(value))
+			continue
+		}
+
+		if value == "" || !part.typ.match(value) {
+			return "", fmt.Errorf("%w: '%s' doesn't match '%s' (got: '%s')", ErrInvalidParameter, part.param, pattern, value)
+		}
+
+		path.WriteString(url.PathEscape(value))
+	}
+
+	return path.String(), nil
+}
+
+// escape - escapes each segment of path keeping separators.
+func escape(path string) string {
+	var segments = strings.Split(path, "/")
+
+	for i, segment := range segments {
+		segments[i] = url.PathEscape(segment)
+	}
+
+	return strings.Join(segments, "/")
+}
@@ -1,170 +1,332 @@
 package pathfinder
 
 import (
-	"errors"
-	"regexp"
+	"fmt"
+	"net/http"
 	"strings"
-
-	"github.com/KlyuchnikovV/engi/internal/request"
 )
 
-var (
-	ErrNotHandled = errors.New("not handled")
-	valueRegexp   = regexp.MustCompile("^[^/]+")
+type (
+	// node - node of radix tree.
+	node struct {
+		// prefix - static part of path matched by node, empty for parameters.
+		prefix string
+		// name - name of parameter matched by node.
+		name string
+		// typ - constraint of parameter matched by node.
+		typ paramType
+
+		edges
+	}
+
+	// edges - children and handler of node, moved together when node is split.
+	edges struct {
+		// indices - first bytes of static children prefixes.
+		indices  string
+		static   []*node
+		params   []*node
+		catchAll *node
+
+		// routes - handlers of path, routes with more specific conditions go first, unconditional one is the last.
+		routes []*route
+	}
+
+	// route - handler of path with conditions request has to satisfy.
+	route struct {
+		key         string
+		specificity int
+		conditions  []Condition
+		handler     Handler
+	}
+
+	// match - state and result of search in tree.
+	match struct {
+		// fold - static parts are compared case-insensitively.
+		fold bool
+
+		params   []param
+		captures map[string]string
+		handler  Handler
+		// tree - path is matched by catch-all parameter or is the root of catch-all route.
+		tree bool
+	}
 )
 
-type HandlerNodes []HanlderNode
+// insert - adds nodes for tokens and adds handler with conditions to the last one.
+func (n *node) insert(tokens []token, handler Handler, conditions []Condition) error {
+	for _, token := range tokens {
+		if token.static != "" {
+			n = n.staticChild(token.static)
+			continue
+		}
+
+		if token.catchAll {
+			child, err := n.catchAllChild(token.param)
+			if err != nil {
+				return err
+			}
+
+			n = child
+
+			continue
+		}
+
+		child, err := n.paramChild(token.param, token.typ)
+		if err != nil {
+			return err
+		}
 
-type HanlderNode interface {
-	Add(handler Handler, parts ...string)
-	GetHandler(request *request.Request, path string) Handler
-	Equal(node HanlderNode) bool
+		n = child
+	}
+
+	var key = conditionsKey(conditions)
+
+	for _, existing := range n.routes {
+		if existing.key == key {
+			return ErrAlreadyRegistered
+		}
+	}
+
+	var (
+		added = &route{key: key, specificity: Specificity(conditions), conditions: conditions, handler: handler}
+		i     = len(n.routes)
+	)
+
+	// Routes are kept sorted by specificity of conditions, unconditional route is always the last one.
+	for i > 0 && len(conditions) != 0 && (n.routes[i-1].key == "" || n.routes[i-1].specificity < added.specificity) {
+		i--
+	}
+
+	n.routes = append(n.routes[:i], append([]*route{added}, n.routes[i:]...)...)
+
+	return nil
 }
 
-func NewHandlerNode(parameter string, handler Handler) HanlderNode {
-	if parameterRegexp.MatchString(parameter) {
-		return NewRegexpHandler(
-			strings.Trim(parameter, "{}"),
-			handler,
+// staticChild - returns node matching static path, creating and splitting nodes if needed.
+func (n *node) staticChild(path string) *node {
+	for path != "" {
+		var i = strings.IndexByte(n.indices, path[0])
+		if i < 0 {
+			var child = &node{prefix: path}
+
+			n.indices += path[:1]
+			n.static = append(n.static, child)
+
+			return child
+		}
+
+		var (
+			child  = n.static[i]
+			common = commonPrefix(child.prefix, path)
 		)
+
+		if common < len(child.prefix) {
+			var split = &node{
+				prefix: child.prefix[common:],
+				edges:  child.edges,
+			}
+
+			child.prefix = child.prefix[:common]
+			child.edges = edges{
+				indices: split.prefix[:1],
+				static:  []*node{split},
+			}
+		}
+
+		n, path = child, path[common:]
 	}
 
-	return NewStringHandler(parameter, handler)
+	return n
 }
 
-type StringHandler struct {
-	pattern string
-	nodes   []HanlderNode
-	Handler
-}
+// paramChild - returns node matching parameter, creating it if needed.
+// Parameters are kept sorted by precedence.
+func (n *node) paramChild(name string, typ paramType) (*node, error) {
+	for _, child := range n.params {
+		if child.typ.key != typ.key {
+			continue
+		}
+
+		if child.name != name {
+			return nil, fmt.Errorf("%w: parameters '{%s}' and '{%s}' match same segment", ErrAmbiguousRoute, child.name, name)
+		}
 
-func NewStringHandler(pattern string, handler Handler) *StringHandler {
-	return &StringHandler{
-		pattern: pattern,
-		Handler: handler,
-		nodes:   make([]HanlderNode, 0),
+		return child, nil
 	}
+
+	var (
+		child = &node{name: name, typ: typ}
+		i     = len(n.params)
+	)
+
+	for i > 0 && typ.precedes(n.params[i-1].typ) {
+		i--
+	}
+
+	n.params = append(n.params[:i], append([]*node{child}, n.params[i:]...)...)
+
+	return child, nil
 }
 
-func (s *StringHandler) Add(handler Handler, parts ...string) {
-	if len(parts) == 0 {
-		return
+// catchAllChild - returns node matching the rest of path, creating it if needed.
+func (n *node) catchAllChild(name string) (*node, error) {
+	if n.catchAll == nil {
+		n.catchAll = &node{name: name, typ: stringType}
 	}
 
-	var newNode = NewHandlerNode(parts[0], handler)
-	for _, node := range s.nodes {
-		if node.Equal(newNode) {
-			node.Add(handler, parts[1:]...)
-			return
-		}
+	if n.catchAll.name != name {
+		return nil, fmt.Errorf("%w: parameters '{%s...}' and '{%s...}' match same segments",
+			ErrAmbiguousRoute, n.catchAll.name, name,
+		)
 	}
 
-	newNode.Add(handler, parts[1:]...)
-	s.nodes = append(s.nodes, newNode)
+	return n.catchAll, nil
 }
 
-func (s *StringHandler) GetHandler(
-	request *request.Request,
-	path string,
-) Handler {
-	path = strings.TrimLeft(path, "/")
+// find - searches route matching path and request, saving matched parameters.
+// Children are tried in order of precedence: static, then typed, regexp constrained and plain parameters,
+// then catch-all parameter.
+func (n *node) find(path string, r *http.Request, found *match) bool {
+	if path == "" {
+		found.tree = n.hasCatchAll()
 
-	if !strings.HasPrefix(path, s.pattern) {
-		return nil
+		return n.route(r, found)
 	}
 
-	var subPath, _ = strings.CutPrefix(path, s.pattern)
+	if i := strings.IndexByte(n.indices, found.first(path)); i >= 0 {
+		var child = n.static[i]
 
-	if len(subPath) == 0 {
-		return s.Handler
+		if found.hasPrefix(path, child.prefix) && child.find(path[len(child.prefix):], r, found) {
+			return true
+		}
 	}
 
-	for _, node := range s.nodes {
-		if handler := node.GetHandler(request, subPath); handler != nil {
-			return handler
-		}
+	if n.findParam(path, r, found) {
+		return true
 	}
 
-	return nil
-}
+	if n.catchAll != nil && n.catchAll.route(r, found) {
+		var value = unescape(path)
 
-func (s *StringHandler) Equal(n HanlderNode) bool {
-	m2, ok := n.(*StringHandler)
-	if !ok {
-		return false
+		found.tree = true
+
+		found.params = append(found.params, param{name: n.catchAll.name, value: value, parsed: value})
+
+		return true
 	}
 
-	return s.pattern == m2.pattern
+	return false
 }
 
-type RegexpHandler struct {
-	name    string
-	pattern regexp.Regexp
-	nodes   []HanlderNode
-	Handler
-}
+// findParam - tries parameter children matching the first segment of path.
+func (n *node) findParam(path string, r *http.Request, found *match) bool {
+	if len(n.params) == 0 {
+		return false
+	}
 
-func NewRegexpHandler(name string, handler Handler) *RegexpHandler {
-	return &RegexpHandler{
-		name:    name,
-		pattern: *valueRegexp,
-		Handler: handler,
-		nodes:   make([]HanlderNode, 0),
+	var end = strings.IndexByte(path, '/')
+	if end < 0 {
+		end = len(path)
 	}
-}
 
-func (r *RegexpHandler) Add(handler Handler, parts ...string) {
-	if len(parts) == 0 {
-		return
+	if end == 0 {
+		return false
 	}
 
-	var newNode = NewHandlerNode(parts[0], handler)
-	for _, node := range r.nodes {
-		if node.Equal(newNode) {
-			node.Add(handler, parts[1:]...)
-			return
+	var segment = unescape(path[:end])
+
+	for _, child := range n.params {
+		if !child.typ.match(segment) {
+			continue
+		}
+
+		parsed, err := child.typ.convert(segment)
+		if err != nil {
+			continue
+		}
+
+		found.params = append(found.params, param{name: child.name, value: segment, parsed: parsed})
+
+		if child.find(path[end:], r, found) {
+			return true
 		}
+
+		found.params = found.params[:len(found.params)-1]
 	}
 
-	newNode.Add(handler, parts[1:]...)
-	r.nodes = append(r.nodes, newNode)
+	return false
 }
 
-func (r *RegexpHandler) GetHandler(
-	request *request.Request,
-	path string,
-) Handler {
-	path = strings.TrimLeft(path, "/")
+// hasCatchAll - checks if node or its '/' child has catch-all parameter.
+func (n *node) hasCatchAll() bool {
+	if n.catchAll != nil {
+		return true
+	}
+
+	var i = strings.IndexByte(n.indices, '/')
 
-	var parts = strings.Split(path, "/")
-	if len(parts) == 0 {
-		return nil
+	return i >= 0 && n.static[i].prefix == "/" && n.static[i].catchAll != nil
+}
+
+// route - selects the first node's route which conditions request satisfies.
+func (n *node) route(r *http.Request, found *match) bool {
+	for _, candidate := range n.routes {
+		captures, ok := matchAll(r, candidate.conditions)
+		if !ok {
+			continue
+		}
+
+		found.captures = captures
+		found.handler = candidate.handler
+
+		return true
 	}
 
-	if !r.pattern.MatchString(parts[0]) {
-		return nil
+	return false
+}
+
+// first - returns the first byte of path, lowered if search is case-insensitive.
+func (found *match) first(path string) byte {
+	if found.fold {
+		return lower(path[0])
 	}
 
-	request.AddInPathParameter(r.name, parts[0])
+	return path[0]
+}
 
-	if len(parts) == 1 {
-		return r.Handler
+// hasPrefix - checks that path starts with static prefix, prefixes of case-insensitive tree are lowered.
+func (found *match) hasPrefix(path, prefix string) bool {
+	if !found.fold {
+		return strings.HasPrefix(path, prefix)
 	}
 
-	for _, node := range r.nodes {
-		if handler := node.GetHandler(request, parts[1]); handler != nil {
-			return handler
+	if len(path) < len(prefix) {
+		return false
+	}
+
+	for i := 0; i < len(prefix); i++ {
+		if lower(path[i]) != prefix[i] {
+			return false
 		}
 	}
 
-	return nil
+	return true
 }
 
-func (r *RegexpHandler) Equal(n HanlderNode) bool {
-	p2, ok := n.(*RegexpHandler)
-	if !ok {
-		return false
+func lower(b byte) byte {
+	if 'A' <= b && b <= 'Z' {
+		return b + 'a' - 'A'
+	}
+
+	return b
+}
+
+func commonPrefix(a, b string) int {
+	var i int
+
+	for i < len(a) && i < len(b) && a[i] == b[i] {
+		i++
 	}
 
-	return r.name == p2.name
+	return i
 }
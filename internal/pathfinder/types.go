@@ -0,0 +1,181 @@
+package pathfinder
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"time"
+
+	"github.com/KlyuchnikovV/engi/internal/request"
+)
+
+const (
+	DateLayout = "2006-01-02"
+
+	uuidLength = 36
+)
+
+// paramType - constraint of path parameter taking part in matching.
+type paramType struct {
+	// key - identifies constraint, parameters with same key match same segments.
+	key string
+	// priority - lower value means higher precedence.
+	priority int
+	// rank - precedence of builtin types matching same segments, narrower types go first.
+	rank int
+
+	match   func(string) bool
+	convert func(string) (interface{}, error)
+}
+
+const (
+	priorityTyped = iota
+	priorityRegexp
+	priorityString
+)
+
+var (
+	stringType = paramType{
+		key:      "",
+		priority: priorityString,
+		match:    func(string) bool { return true },
+		convert:  func(value string) (interface{}, error) { return value, nil },
+	}
+
+	builtinTypes = map[string]paramType{
+		"int": {
+			key:      "int",
+			priority: priorityTyped,
+			rank:     0,
+			match:    isInteger,
+			convert: func(value string) (interface{}, error) {
+				return strconv.ParseInt(value, request.IntBase, request.BitSize)
+			},
+		},
+		"float": {
+			key:      "float",
+			priority: priorityTyped,
+			rank:     4,
+			match: func(value string) bool {
+				_, err := strconv.ParseFloat(value, request.BitSize)
+				return err == nil
+			},
+			convert: func(value string) (interface{}, error) {
+				return strconv.ParseFloat(value, request.BitSize)
+			},
+		},
+		"bool": {
+			key:      "bool",
+			priority: priorityTyped,
+			rank:     3,
+			match: func(value string) bool {
+				_, err := strconv.ParseBool(value)
+				return err == nil
+			},
+			convert: func(value string) (interface{}, error) {
+				return strconv.ParseBool(value)
+			},
+		},
+		"uuid": {
+			key:      "uuid",
+			priority: priorityTyped,
+			rank:     1,
+			match:    isUUID,
+			convert:  func(value string) (interface{}, error) { return value, nil },
+		},
+		"date": {
+			key:      "date",
+			priority: priorityTyped,
+			rank:     2,
+			match: func(value string) bool {
+				_, err := time.Parse(DateLayout, value)
+				return err == nil
+			},
+			convert: func(value string) (interface{}, error) {
+				return time.Parse(DateLayout, value)
+			},
+		},
+		"string": stringType,
+	}
+)
+
+// precedes - checks if parameter of type is tried before parameter of other type:
+// by priority, then builtin types by rank (int, uuid, date, bool, float)
+// and regular expressions in lexical order of their constraints, so precedence doesn't depend on order of routes.
+func (typ paramType) precedes(other paramType) bool {
+	if typ.priority != other.priority {
+		return typ.priority < other.priority
+	}
+
+	if typ.rank != other.rank {
+		return typ.rank < other.rank
+	}
+
+	return typ.key < other.key
+}
+
+// newParamType - returns builtin type by name or compiles constraint as regular expression.
+func newParamType(constraint string) (paramType, error) {
+	if constraint == "" {
+		return stringType, nil
+	}
+
+	if typ, ok := builtinTypes[constraint]; ok {
+		return typ, nil
+	}
+
+	expression, err := regexp.Compile(fmt.Sprintf("^(?:%s)$", constraint))
+	if err != nil {
+		return paramType{}, fmt.Errorf("%w: invalid constraint '%s': %w", ErrInvalidPattern, constraint, err)
+	}
+
+	return paramType{
+		key:      "regexp:" + constraint,
+		priority: priorityRegexp,
+		match:    expression.MatchString,
+		convert:  func(value string) (interface{}, error) { return value, nil },
+	}, nil
+}
+
+func isInteger(value string) bool {
+	if len(value) != 0 && (value[0] == '-' || value[0] == '+') {
+		value = value[1:]
+	}
+
+	if len(value) == 0 {
+		return false
+	}
+
+	for i := 0; i < len(value); i++ {
+		if value[i] < '0' || value[i] > '9' {
+			return false
+		}
+	}
+
+	return true
+}
+
+func isUUID(value string) bool {
+	if len(value) != uuidLength {
+		return false
+	}
+
+	for i := 0; i < len(value); i++ {
+		switch i {
+		case 8, 13, 18, 23:
+			if value[i] != '-' {
+				return false
+			}
+		default:
+			if !isHex(value[i]) {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+func isHex(c byte) bool {
+	return ('0' <= c && c <= '9') || ('a' <= c && c <= 'f') || ('A' <= c && c <= 'F')
+}
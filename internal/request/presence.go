@@ -0,0 +1,112 @@
+package request
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/KlyuchnikovV/engi/parameter/placing"
+)
+
+// Presence - option defining handling of parameter missing in request, see 'Optional' and 'Default'.
+// Presence is taken from options on parameter's registration and is not a check of parameter's value.
+type Presence struct {
+	optional bool
+	fallback interface{}
+}
+
+// Optional - allows parameter to be missing in request, checks are applied only to passed values.
+func Optional() Presence {
+	return Presence{optional: true}
+}
+
+// Default - allows parameter to be missing in request and sets 'value' to it instead.
+func Default(value interface{}) Presence {
+	return Presence{optional: true, fallback: value}
+}
+
+// Validate - presence doesn't check parameter's value.
+func (Presence) Validate(*Parameter) error {
+	return nil
+}
+
+// Validate - checks parameter's value.
+func (validator Validator) Validate(p *Parameter) error {
+	return validator(p)
+}
+
+// PresenceOf - splits options of parameter 'key' with value of type of 'zero' into presence
+// set by the last of 'Optional' and 'Default' options and the rest of options.
+// Default value is converted to type of 'zero'.
+func PresenceOf(key string, opts []Option, zero interface{}) (Presence, []Option) {
+	var (
+		presence Presence
+		rest     = make([]Option, 0, len(opts))
+	)
+
+	for _, opt := range opts {
+		if typed, ok := opt.(Presence); ok {
+			presence = typed
+			continue
+		}
+
+		rest = append(rest, opt)
+	}
+
+	if presence.fallback != nil {
+		presence.fallback = convertDefault(key, presence.fallback, reflect.TypeOf(zero))
+	}
+
+	return presence, rest
+}
+
+// convertDefault - converts default 'value' of parameter 'key' to type 'to' panicking if it's impossible.
+func convertDefault(key string, value interface{}, to reflect.Type) interface{} {
+	var from = reflect.ValueOf(value)
+
+	if from.Type() == to {
+		return value
+	}
+
+	if from.Kind() == reflect.Slice && to.Kind() == reflect.Slice && convertible(from.Type().Elem(), to.Elem()) {
+		var result = reflect.MakeSlice(to, from.Len(), from.Len())
+
+		for i := 0; i < from.Len(); i++ {
+			result.Index(i).Set(from.Index(i).Convert(to.Elem()))
+		}
+
+		return result.Interface()
+	}
+
+	if convertible(from.Type(), to) {
+		return from.Convert(to).Interface()
+	}
+
+	panic(fmt.Errorf("default value of parameter '%s' must be of type '%s' (got: '%T')", key, to, value))
+}
+
+// convertible - checks if value can be converted without changing its meaning (e.g. int to string is not).
+func convertible(from, to reflect.Type) bool {
+	return from.ConvertibleTo(to) && isNumber(from.Kind()) == isNumber(to.Kind())
+}
+
+func isNumber(kind reflect.Kind) bool {
+	return reflect.Int <= kind && kind <= reflect.Float64
+}
+
+// setAbsent - saves optional parameter missing in request with its default value.
+func (r *Request) setAbsent(key string, paramPlacing placing.Placing, presence Presence) {
+	if r.parameters[paramPlacing] == nil {
+		r.parameters[paramPlacing] = make(map[string]Parameter)
+	}
+
+	var parameter = r.parameters[paramPlacing][key]
+
+	r.parameters[paramPlacing][key] = Parameter{
+		Name:         key,
+		Parsed:       presence.fallback,
+		raw:          parameter.raw,
+		Description:  parameter.Description,
+		wasRequested: true,
+		absent:       true,
+	}
+}
@@ -3,6 +3,7 @@ package request
 import (
 	"fmt"
 	"net/http"
+	"reflect"
 	"strconv"
 	"strings"
 	"time"
@@ -17,9 +18,14 @@ const (
 )
 
 type (
-	Option                func(*Parameter) error
-	Middleware            func(r *Request, w http.ResponseWriter) *response.AsObject
-	ParamsValidator       interface {
+	// Option - option of parameter: Validator checking its value or Presence handling its absence.
+	Option interface {
+		Validate(p *Parameter) error
+	}
+	// Validator - checks parameter's value.
+	Validator       func(*Parameter) error
+	Middleware      func(r *Request, w http.ResponseWriter) *response.AsObject
+	ParamsValidator interface {
 		Validate(param string) error
 	}
 
@@ -33,28 +39,56 @@ type (
 		// GetRequest - return http.Request object associated with request.
 		GetRequest() *http.Request
 		// Body - returns request body.
-		// Body must be requested by 'api.Body(pointer)' or 'api.CustomBody(unmarshaler, pointer)'.
+		// Body must be requested by 'parameter.BodyOf[T]()' or 'parameter.CustomBodyOf[T](unmarshaler)'.
 		Body() interface{}
+		// Bound - returns structure of type bound by 'parameter.Bind[T]()'.
+		Bound(typ reflect.Type) (interface{}, bool)
 		// Bool - returns boolean parameter.
 		// Mandatory parameter should be requested by 'api.Bool'.
 		// Otherwise, parameter will be obtained by key and its value will be checked for truth.
 		Bool(value string, place placing.Placing) bool
+		// BoolOK - returns boolean parameter and false if it's missing in request (even if default value is set).
+		BoolOK(value string, place placing.Placing) (bool, bool)
 		// Integer - returns integer parameter.
 		// Mandatory parameter should be requested by 'api.Integer'.
 		// Otherwise, parameter will be obtained by key and its value will be converted. to int64.
 		Integer(value string, place placing.Placing) int64
+		// IntegerOK - returns integer parameter and false if it's missing in request (even if default value is set).
+		IntegerOK(value string, place placing.Placing) (int64, bool)
 		// Float - returns floating point number parameter.
 		// Mandatory parameter should be requested by 'api.Float'.
 		// Otherwise, parameter will be obtained by key and its value will be converted to float64.
 		Float(value string, place placing.Placing) float64
+		// FloatOK - returns floating point number parameter and false if it's missing in request (even if default value is set).
+		FloatOK(value string, place placing.Placing) (float64, bool)
 		// String - returns String parameter.
 		// Mandatory parameter should be requested by 'api.String'.
 		// Otherwise, parameter will be obtained by key.
 		String(value string, place placing.Placing) string
+		// StringOK - returns string parameter and false if it's missing in request (even if default value is set).
+		StringOK(value string, place placing.Placing) (string, bool)
 		// Time - returns date-time parameter.
 		// Mandatory parameter should be requested by 'api.Time'.
 		// Otherwise, parameter will be obtained by key and its value will be converted to time using 'layout'.
 		Time(key string, layout string, paramPlacing placing.Placing) time.Time
+		// TimeOK - returns date-time parameter and false if it's missing in request (even if default value is set).
+		TimeOK(key string, layout string, paramPlacing placing.Placing) (time.Time, bool)
+		// Integers - returns list of integers parameter.
+		// Mandatory parameter should be requested by 'api.Integers'.
+		// Otherwise, all values of parameter will be obtained by key and converted to int64 skipping invalid ones.
+		Integers(key string, paramPlacing placing.Placing) []int64
+		// Floats - returns list of floating point numbers parameter.
+		// Mandatory parameter should be requested by 'api.Floats'.
+		// Otherwise, all values of parameter will be obtained by key and converted to float64 skipping invalid ones.
+		Floats(key string, paramPlacing placing.Placing) []float64
+		// Strings - returns list of strings parameter.
+		// Mandatory parameter should be requested by 'api.Strings'.
+		// Otherwise, all values of parameter will be obtained by key.
+		Strings(key string, paramPlacing placing.Placing) []string
+		// Times - returns list of date-time parameter.
+		// Mandatory parameter should be requested by 'api.Times'.
+		// Otherwise, all values of parameter will be obtained by key and converted to time using 'layout' skipping invalid ones.
+		Times(key string, layout string, paramPlacing placing.Placing) []time.Time
 	}
 )
 
@@ -62,6 +96,8 @@ type Parameter struct {
 	raw          []string
 	Parsed       interface{}
 	wasRequested bool
+	// absent - parameter is optional and was not passed in request.
+	absent bool
 
 	Name        string
 	Description string
@@ -73,6 +109,7 @@ type Request struct {
 
 	body       Parameter
 	parameters map[placing.Placing]map[string]Parameter
+	bound      map[reflect.Type]interface{}
 
 	Description string
 }
@@ -119,69 +156,158 @@ func New(request *http.Request) *Request {
 }
 
 func (r *Request) Bool(key string, paramPlacing placing.Placing) bool {
-	if r.isMandatoryParam(key, paramPlacing) {
-		if result, ok := r.parameters[paramPlacing][key].Parsed.(bool); ok {
-			return result
-		}
-
-		panic(fmt.Errorf("conversion parameter to bool failed (key: %s)", key))
-	}
-
-	result, _ := strconv.ParseBool(r.String(key, paramPlacing))
+	result, _ := r.BoolOK(key, paramPlacing)
 
 	return result
 }
 
+func (r *Request) BoolOK(key string, paramPlacing placing.Placing) (bool, bool) {
+	return valueOf(r, key, paramPlacing, strconv.ParseBool)
+}
+
 func (r *Request) Integer(key string, paramPlacing placing.Placing) int64 {
-	if r.isMandatoryParam(key, paramPlacing) {
-		if result, ok := r.parameters[paramPlacing][key].Parsed.(int64); ok {
-			return result
-		}
+	result, _ := r.IntegerOK(key, paramPlacing)
 
-		panic(fmt.Errorf("conversion parameter to int64 failed (key: %s)", key))
-	}
+	return result
+}
 
-	result, _ := strconv.ParseInt(r.String(key, paramPlacing), IntBase, BitSize)
+func (r *Request) IntegerOK(key string, paramPlacing placing.Placing) (int64, bool) {
+	return valueOf(r, key, paramPlacing, func(value string) (int64, error) {
+		return strconv.ParseInt(value, IntBase, BitSize)
+	})
+}
+
+func (r *Request) Float(key string, paramPlacing placing.Placing) float64 {
+	result, _ := r.FloatOK(key, paramPlacing)
 
 	return result
 }
 
-func (r *Request) Float(key string, paramPlacing placing.Placing) float64 {
-	if r.isMandatoryParam(key, paramPlacing) {
-		if result, ok := r.parameters[paramPlacing][key].Parsed.(float64); ok {
-			return result
-		}
+func (r *Request) FloatOK(key string, paramPlacing placing.Placing) (float64, bool) {
+	return valueOf(r, key, paramPlacing, func(value string) (float64, error) {
+		return strconv.ParseFloat(value, BitSize)
+	})
+}
 
-		panic(fmt.Errorf("conversion parameter to float64 failed (key: %s)", key))
-	}
+func (r *Request) String(key string, paramPlacing placing.Placing) string {
+	result, _ := r.StringOK(key, paramPlacing)
 
-	result, _ := strconv.ParseFloat(r.String(key, paramPlacing), BitSize)
+	return result
+}
+
+func (r *Request) StringOK(key string, paramPlacing placing.Placing) (string, bool) {
+	return valueOf(r, key, paramPlacing, func(value string) (string, error) {
+		return value, nil
+	})
+}
+
+func (r *Request) Time(key, layout string, paramPlacing placing.Placing) time.Time {
+	result, _ := r.TimeOK(key, layout, paramPlacing)
 
 	return result
 }
 
-func (r *Request) String(key string, paramPlacing placing.Placing) string {
-	if r.isMandatoryParam(key, paramPlacing) {
-		if result, ok := r.parameters[paramPlacing][key].Parsed.(string); ok {
-			return result
+func (r *Request) TimeOK(key, layout string, paramPlacing placing.Placing) (time.Time, bool) {
+	return valueOf(r, key, paramPlacing, func(value string) (time.Time, error) {
+		return time.Parse(layout, value)
+	})
+}
+
+// valueOf - returns parameter requested by middleware or converts value of parameter by key,
+// declared parameter requested with another type is converted from its raw value.
+// Result is false if parameter is missing in request or can't be converted,
+// panics if declared parameter is missing in route or can't be converted to requested type.
+func valueOf[T any](r *Request, key string, paramPlacing placing.Placing, convert func(string) (T, error)) (T, bool) {
+	var (
+		parameter = r.parameters[paramPlacing][key]
+		mandatory = r.isMandatoryParam(key, paramPlacing)
+		zero      T
+	)
+
+	if mandatory {
+		if result, ok := parameter.Parsed.(T); ok {
+			return result, !parameter.absent
+		}
+
+		if parameter.absent {
+			return zero, false
 		}
 
-		panic(fmt.Errorf("conversion parameter to string failed (key: %s)", key))
+		if len(parameter.raw) == 0 {
+			panic(fmt.Errorf("conversion parameter to %T failed (key: %s)", zero, key))
+		}
 	}
 
-	return r.GetParameter(key, paramPlacing)
-}
+	if len(parameter.raw) == 0 {
+		return zero, false
+	}
 
-func (r *Request) Time(key, layout string, paramPlacing placing.Placing) time.Time {
-	if r.isMandatoryParam(key, paramPlacing) {
-		if result, ok := r.parameters[paramPlacing][key].Parsed.(time.Time); ok {
-			return result
+	result, err := convert(r.GetParameter(key, paramPlacing))
+	if err != nil {
+		if mandatory {
+			// Parameter is declared with another type (e.g. typed path placeholder), so route is misconfigured.
+			panic(fmt.Errorf("conversion parameter to %T failed (key: %s): %w", zero, key, err))
 		}
 
-		panic(fmt.Errorf("conversion parameter to time failed (key: %s)", key))
+		return zero, false
 	}
 
-	result, _ := time.Parse(layout, r.String(key, paramPlacing))
+	return result, true
+}
+
+func (r *Request) Integers(key string, paramPlacing placing.Placing) []int64 {
+	return listOf(r, key, paramPlacing, func(value string) (int64, error) {
+		return strconv.ParseInt(value, IntBase, BitSize)
+	})
+}
+
+func (r *Request) Floats(key string, paramPlacing placing.Placing) []float64 {
+	return listOf(r, key, paramPlacing, func(value string) (float64, error) {
+		return strconv.ParseFloat(value, BitSize)
+	})
+}
+
+func (r *Request) Strings(key string, paramPlacing placing.Placing) []string {
+	return listOf(r, key, paramPlacing, func(value string) (string, error) {
+		return value, nil
+	})
+}
+
+func (r *Request) Times(key, layout string, paramPlacing placing.Placing) []time.Time {
+	return listOf(r, key, paramPlacing, func(value string) (time.Time, error) {
+		return time.Parse(layout, value)
+	})
+}
+
+// listOf - returns list parameter requested by list middleware or converts all values of parameter by key.
+// Parameter requested with another type is converted from its raw values.
+func listOf[T any](r *Request, key string, paramPlacing placing.Placing, convert func(string) (T, error)) []T {
+	var parameter = r.parameters[paramPlacing][key]
+
+	if result, ok := parameter.Parsed.([]T); ok || parameter.absent {
+		return result
+	}
+
+	if len(parameter.raw) == 0 && r.isMandatoryParam(key, paramPlacing) {
+		panic(fmt.Errorf("conversion parameter to %T failed (key: %s)", []T{}, key))
+	}
+
+	var (
+		result    = make([]T, 0, len(parameter.raw))
+		mandatory = r.isMandatoryParam(key, paramPlacing)
+	)
+
+	for _, value := range parameter.raw {
+		converted, err := convert(value)
+		if err == nil {
+			result = append(result, converted)
+			continue
+		}
+
+		if mandatory {
+			panic(fmt.Errorf("conversion parameter to %T failed (key: %s): %w", []T{}, key, err))
+		}
+	}
 
 	return result
 }
@@ -205,15 +331,13 @@ func (r *Request) Body() interface{} {
 }
 
 func (r *Request) isMandatoryParam(key string, paramPlacing placing.Placing) bool {
-	switch paramPlacing {
-	case placing.InPath:
+	if paramPlacing == placing.InPath {
 		return true
-	case placing.InQuery:
-		param, ok := r.parameters[paramPlacing][key]
-		return ok && param.wasRequested
-	default:
-		return false
 	}
+
+	param, ok := r.parameters[paramPlacing][key]
+
+	return ok && param.wasRequested
 }
 
 func (r *Request) GetParameter(key string, paramPlacing placing.Placing) string {
@@ -232,18 +356,48 @@ func (r *Request) GetRequest() *http.Request {
 	return r.request
 }
 
-func (r *Request) AddInPathParameter(key string, value string) {
+// AddInPathParameter - saves path parameter with value converted according to its type in route pattern.
+func (r *Request) AddInPathParameter(key string, value string, parsed interface{}) {
 	if r.parameters[placing.InPath] == nil {
 		r.parameters[placing.InPath] = make(map[string]Parameter)
 	}
 
 	r.parameters[placing.InPath][key] = Parameter{
 		raw:          []string{value},
+		Parsed:       parsed,
 		wasRequested: true,
 		Name:         key,
 	}
 }
 
+// SetBound - saves structure bound from request's parameters.
+func (r *Request) SetBound(value interface{}) {
+	if r.bound == nil {
+		r.bound = make(map[reflect.Type]interface{})
+	}
+
+	r.bound[reflect.TypeOf(value)] = value
+}
+
+// Bound - returns structure of type bound from request's parameters.
+func (r *Request) Bound(typ reflect.Type) (interface{}, bool) {
+	value, ok := r.bound[typ]
+
+	return value, ok
+}
+
+// AddInHostParameter - saves value captured from host by route's host pattern.
+func (r *Request) AddInHostParameter(key string, value string) {
+	if r.parameters[placing.InHost] == nil {
+		r.parameters[placing.InHost] = make(map[string]Parameter)
+	}
+
+	r.parameters[placing.InHost][key] = Parameter{
+		raw:  []string{value},
+		Name: key,
+	}
+}
+
 func (r *Request) Headers() map[string][]string {
 	return r.request.Header
 }
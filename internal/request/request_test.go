@@ -0,0 +1,68 @@
+package request
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/KlyuchnikovV/engi/parameter/placing"
+)
+
+func TestTypedPathParameterAccessors(t *testing.T) {
+	var r = New(httptest.NewRequest("GET", "/items/5/x", nil))
+
+	r.AddInPathParameter("id", "5", int64(5))
+
+	if id := r.Integer("id", placing.InPath); id != 5 {
+		t.Fatalf("expected integer 5, got %d", id)
+	}
+
+	if id := r.String("id", placing.InPath); id != "5" {
+		t.Fatalf("expected string '5', got '%s'", id)
+	}
+
+	if id := r.Float("id", placing.InPath); id != 5 {
+		t.Fatalf("expected float 5, got %f", id)
+	}
+
+	if ids := r.Strings("id", placing.InPath); len(ids) != 1 || ids[0] != "5" {
+		t.Fatalf("expected strings [5], got %v", ids)
+	}
+}
+
+func TestTypedPathParameterWithAnotherTypePanics(t *testing.T) {
+	var r = New(httptest.NewRequest("GET", "/items/5/x", nil))
+
+	r.AddInPathParameter("id", "5", int64(5))
+
+	defer func() {
+		if recover() == nil {
+			t.Fatal("expected panic for 'int' path parameter requested as bool")
+		}
+	}()
+
+	r.BoolOK("id", placing.InPath)
+}
+
+func TestUndeclaredParameterIsNotConverted(t *testing.T) {
+	var r = New(httptest.NewRequest("GET", "/items?flag=5", nil))
+
+	if _, ok := r.BoolOK("flag", placing.InQuery); ok {
+		t.Fatal("expected '5' not to be converted to bool")
+	}
+
+	if flags := r.Integers("flag", placing.InQuery); len(flags) != 1 || flags[0] != 5 {
+		t.Fatalf("expected integers [5], got %v", flags)
+	}
+}
+
+func TestMissingPathParameterPanics(t *testing.T) {
+	var r = New(httptest.NewRequest("GET", "/items", nil))
+
+	defer func() {
+		if recover() == nil {
+			t.Fatal("expected panic for path parameter missing in route")
+		}
+	}()
+
+	r.String("id", placing.InPath)
+}
@@ -6,6 +6,7 @@ import (
 	"errors"
 	"io"
 	"net/http"
+	"strings"
 
 	"github.com/KlyuchnikovV/engi/internal/types"
 	"github.com/KlyuchnikovV/engi/parameter/placing"
@@ -13,16 +14,23 @@ import (
 )
 
 // ExtractParam - extracting parameter from context, calls middleware and saves to 'context.parameters[from][key]'.
+// Missing parameter is saved with default value if 'presence' allows it, otherwise 400 is responded.
 // After this parameter can be retrieved from context using 'context.Query' methods.
 func ExtractParam(
 	key string,
 	paramPlacing placing.Placing,
 	request *Request,
+	presence Presence,
 	configs []Option,
 	convert func(string) (interface{}, error),
 ) *response.AsObject {
 	var param = request.GetParameter(key, paramPlacing)
 	if len(param) == 0 {
+		if presence.optional {
+			request.setAbsent(key, paramPlacing, presence)
+			return nil
+		}
+
 		return response.AsError(http.StatusBadRequest, "parameter '%s' not found", key)
 	}
 
@@ -45,7 +53,7 @@ func ExtractParam(
 
 	var parameter = request.parameters[paramPlacing][key]
 	for _, config := range configs {
-		if err := config(&parameter); err != nil {
+		if err := config.Validate(&parameter); err != nil {
 			return response.AsError(http.StatusBadRequest, err.Error())
 		}
 	}
@@ -71,8 +79,12 @@ func ExtractBody(request *Request, unmarshaler types.Unmarshaler, pointer interf
 		return response.AsError(http.StatusBadRequest, err.Error())
 	}
 
+	// Body is saved here, so it's available with custom unmarshalers too.
+	request.body.wasRequested = true
+	request.body.Parsed = pointer
+
 	for _, config := range configs {
-		if err := config(&request.body); err != nil {
+		if err := config.Validate(&request.body); err != nil {
 			return response.AsError(http.StatusBadRequest, err.Error())
 		}
 	}
@@ -111,9 +123,6 @@ func GetUnmarshaler(request *Request) (types.Unmarshaler, error) {
 			return response.AsError(http.StatusInternalServerError, "unmarshaling body failed: %s", err.Error())
 		}
 
-		request.body.wasRequested = true
-		request.body.Parsed = pointer
-
 		return nil
 	}, nil
 }
@@ -136,3 +145,72 @@ func readBody(request *Request) error {
 
 	return err
 }
+
+// ExtractList - extracting list parameter from all values of repeated 'key' split by any of 'delimiters',
+// calls middleware and saves to 'context.parameters[from][key]'.
+// After this parameter can be retrieved from context using 'context.Integers' and other list methods.
+func ExtractList(
+	key string,
+	paramPlacing placing.Placing,
+	request *Request,
+	delimiters string,
+	presence Presence,
+	configs []Option,
+	convert func([]string) (interface{}, error),
+) *response.AsObject {
+	var values = splitValues(request.parameters[paramPlacing][key].raw, delimiters)
+	if len(values) == 0 {
+		if presence.optional {
+			request.setAbsent(key, paramPlacing, presence)
+			return nil
+		}
+
+		return response.AsError(http.StatusBadRequest, "parameter '%s' not found", key)
+	}
+
+	result, err := convert(values)
+	if err != nil {
+		return response.AsError(http.StatusBadRequest, err.Error())
+	}
+
+	var parameter = Parameter{
+		Name:         key,
+		Parsed:       result,
+		raw:          request.parameters[paramPlacing][key].raw,
+		Description:  request.parameters[paramPlacing][key].Description,
+		wasRequested: true,
+	}
+
+	for _, config := range configs {
+		if err := config.Validate(&parameter); err != nil {
+			return response.AsError(http.StatusBadRequest, err.Error())
+		}
+	}
+
+	request.parameters[paramPlacing][key] = parameter
+
+	return nil
+}
+
+// splitValues - splits every value by any of 'delimiters' runes, trims spaces and skips empty elements.
+func splitValues(values []string, delimiters string) []string {
+	var (
+		result      = make([]string, 0, len(values))
+		isDelimiter = func(r rune) bool { return strings.ContainsRune(delimiters, r) }
+	)
+
+	for _, value := range values {
+		var elements = []string{value}
+		if len(delimiters) != 0 {
+			elements = strings.FieldsFunc(value, isDelimiter)
+		}
+
+		for _, element := range elements {
+			if element = strings.TrimSpace(element); len(element) != 0 {
+				result = append(result, element)
+			}
+		}
+	}
+
+	return result
+}
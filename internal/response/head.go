@@ -0,0 +1,21 @@
+package response
+
+import "net/http"
+
+// HeadWriter - drops response body, used to serve HEAD requests by GET handlers.
+type HeadWriter struct {
+	http.ResponseWriter
+}
+
+func NewHeadWriter(writer http.ResponseWriter) *HeadWriter {
+	return &HeadWriter{ResponseWriter: writer}
+}
+
+func (hw *HeadWriter) Write(bytes []byte) (int, error) {
+	return len(bytes), nil
+}
+
+// Unwrap - returns underlying writer, so http.ResponseController can flush or hijack it.
+func (hw *HeadWriter) Unwrap() http.ResponseWriter {
+	return hw.ResponseWriter
+}
@@ -3,6 +3,7 @@ package response
 import (
 	"fmt"
 	"net/http"
+	"reflect"
 
 	"github.com/KlyuchnikovV/engi/internal/types"
 )
@@ -17,6 +18,9 @@ type Responser interface {
 	WithoutContent(code int) error
 	// Error - responses custom error with provided code and formatted string message.
 	Error(code int, format string, args ...interface{}) error
+	// ErrorWithDetails - responses custom error with provided code, message and details.
+	// Details are written only if response object supports them (e.g. 'response.AsObject').
+	ErrorWithDetails(code int, message string, details interface{}) error
 	// OK - writes payload into json's 'result' field with 200 http code.
 	OK(payload interface{}) error
 	// Created - responses with 201 http code and no content.
@@ -50,10 +54,28 @@ func New(
 	return &Response{
 		writer:    writer,
 		marshaler: marshaler,
-		object:    object,
+		object:    copyObject(object),
 	}
 }
 
+// copyObject - copies response object so that concurrent responses don't share payload, error and details.
+func copyObject(object types.Responser) types.Responser {
+	var value = reflect.ValueOf(object)
+	if value.Kind() != reflect.Pointer || value.IsNil() {
+		return object
+	}
+
+	var copied = reflect.New(value.Elem().Type())
+	copied.Elem().Set(value.Elem())
+
+	result, ok := copied.Interface().(types.Responser)
+	if !ok {
+		return object
+	}
+
+	return result
+}
+
 func (resp *Response) Object(code int, payload interface{}) error {
 	resp.object.SetPayload(payload)
 
@@ -95,6 +117,14 @@ func (resp *Response) Error(code int, format string, args ...interface{}) error
 	return err
 }
 
+func (resp *Response) ErrorWithDetails(code int, message string, details interface{}) error {
+	if object, ok := resp.object.(types.DetailsSetter); ok && details != nil {
+		object.SetDetails(details)
+	}
+
+	return resp.Error(code, "%s", message)
+}
+
 func (resp *Response) WithoutContent(code int) error {
 	resp.writer.WriteHeader(code)
 	return nil // in purpose of unification
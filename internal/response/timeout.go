@@ -0,0 +1,140 @@
+package response
+
+import (
+	"bytes"
+	"net/http"
+	"sync"
+)
+
+// TimeoutWriter - buffers response of handler running with deadline,
+// so it can be dropped if deadline exceeds before handler finishes.
+// Flushed response is committed and written directly, so streaming handlers work under deadline.
+type TimeoutWriter struct {
+	writer http.ResponseWriter
+
+	mutex       sync.Mutex
+	header      http.Header
+	buffer      bytes.Buffer
+	code        int
+	wroteHeader bool
+	timedOut    bool
+	committed   bool
+}
+
+func NewTimeoutWriter(writer http.ResponseWriter) *TimeoutWriter {
+	return &TimeoutWriter{
+		writer: writer,
+		header: make(http.Header),
+		code:   http.StatusOK,
+	}
+}
+
+func (tw *TimeoutWriter) Header() http.Header {
+	return tw.header
+}
+
+func (tw *TimeoutWriter) Write(bytes []byte) (int, error) {
+	tw.mutex.Lock()
+	defer tw.mutex.Unlock()
+
+	if tw.timedOut {
+		return 0, http.ErrHandlerTimeout
+	}
+
+	tw.wroteHeader = true
+
+	if tw.committed {
+		return tw.writer.Write(bytes)
+	}
+
+	return tw.buffer.Write(bytes)
+}
+
+func (tw *TimeoutWriter) WriteHeader(code int) {
+	tw.mutex.Lock()
+	defer tw.mutex.Unlock()
+
+	if tw.timedOut || tw.wroteHeader {
+		return
+	}
+
+	tw.code = code
+	tw.wroteHeader = true
+}
+
+// TimedOut - marks writer as timed out, all further writes are dropped.
+func (tw *TimeoutWriter) TimedOut() {
+	tw.mutex.Lock()
+	defer tw.mutex.Unlock()
+
+	tw.timedOut = true
+}
+
+// Committed - checks if response was flushed, so it can't be replaced anymore.
+func (tw *TimeoutWriter) Committed() bool {
+	tw.mutex.Lock()
+	defer tw.mutex.Unlock()
+
+	return tw.committed
+}
+
+// Written - checks if handler has written anything into response.
+func (tw *TimeoutWriter) Written() bool {
+	tw.mutex.Lock()
+	defer tw.mutex.Unlock()
+
+	return tw.wroteHeader
+}
+
+// Commit - writes buffered response into underlying writer.
+func (tw *TimeoutWriter) Commit() error {
+	tw.mutex.Lock()
+	defer tw.mutex.Unlock()
+
+	return tw.commit()
+}
+
+// FlushError - commits buffered response and flushes underlying writer, used by http.ResponseController.
+func (tw *TimeoutWriter) FlushError() error {
+	tw.mutex.Lock()
+	defer tw.mutex.Unlock()
+
+	if tw.timedOut {
+		return http.ErrHandlerTimeout
+	}
+
+	if err := tw.commit(); err != nil {
+		return err
+	}
+
+	return http.NewResponseController(tw.writer).Flush()
+}
+
+// Flush - implements http.Flusher.
+func (tw *TimeoutWriter) Flush() {
+	_ = tw.FlushError()
+}
+
+// Unwrap - returns underlying writer, so http.ResponseController can reach its other features (e.g. hijacking).
+func (tw *TimeoutWriter) Unwrap() http.ResponseWriter {
+	return tw.writer
+}
+
+// commit - writes headers once and moves buffered body into underlying writer.
+func (tw *TimeoutWriter) commit() error {
+	if !tw.committed {
+		for key, values := range tw.header {
+			tw.writer.Header()[key] = values
+		}
+
+		tw.writer.WriteHeader(tw.code)
+
+		tw.committed = true
+		tw.wroteHeader = true
+	}
+
+	_, err := tw.writer.Write(tw.buffer.Bytes())
+	tw.buffer.Reset()
+
+	return err
+}
@@ -0,0 +1,237 @@
+package static
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"mime"
+	"net/http"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	DefaultIndex = "index.html"
+
+	gzipSuffix = ".gz"
+)
+
+// Files - serves files of file system with index files, ETags, ranges and precompressed gzip siblings.
+type Files struct {
+	fsys  fs.FS
+	index string
+	spa   bool
+
+	// etags - cached ETags of files by name, size and modification time.
+	etags sync.Map
+}
+
+// New - creates files server, if 'spa' is set index file is served for unknown paths.
+func New(fsys fs.FS, index string, spa bool) *Files {
+	if index == "" {
+		index = DefaultIndex
+	}
+
+	return &Files{
+		fsys:  fsys,
+		index: index,
+		spa:   spa,
+	}
+}
+
+// Serve - responds with file by name relative to file system root, 'requested' is escaped path requested by client
+// used to redirect directories. Returns fs.ErrNotExist if neither file nor SPA fallback was found.
+func (files *Files) Serve(w http.ResponseWriter, r *http.Request, name, requested string) error {
+	name = strings.TrimPrefix(path.Clean("/"+name), "/")
+	if name == "" {
+		name = "."
+	}
+
+	err := files.serve(w, r, name, requested)
+	if errors.Is(err, fs.ErrNotExist) && files.spa {
+		return files.serve(w, r, files.index, requested)
+	}
+
+	return err
+}
+
+// serve - responds with file or index file of directory.
+func (files *Files) serve(w http.ResponseWriter, r *http.Request, name, requested string) error {
+	info, err := fs.Stat(files.fsys, name)
+	if err != nil {
+		return err
+	}
+
+	if info.IsDir() {
+		// Directory is redirected to path with trailing slash, so relative links of index file are resolved within it.
+		if !strings.HasSuffix(requested, "/") {
+			redirect(w, r, requested+"/")
+			return nil
+		}
+
+		name = path.Join(name, files.index)
+
+		if info, err = fs.Stat(files.fsys, name); err != nil {
+			return err
+		}
+
+		if info.IsDir() {
+			return fmt.Errorf("%w: '%s' is a directory", fs.ErrNotExist, name)
+		}
+	}
+
+	var (
+		header      = w.Header()
+		contentType = mime.TypeByExtension(filepath.Ext(name))
+	)
+
+	if contentType != "" {
+		header.Set("Content-Type", contentType)
+	}
+
+	// Precompressed sibling is served instead of file if client accepts gzip.
+	if gzipped, ok := files.gzipped(name); ok {
+		header.Add("Vary", "Accept-Encoding")
+
+		if acceptsGzip(r.Header.Get("Accept-Encoding")) {
+			header.Set("Content-Encoding", "gzip")
+
+			name, info = gzipped, nil
+		}
+	}
+
+	content, modTime, err := files.open(name, info)
+	if err != nil {
+		return err
+	}
+	defer content.Close()
+
+	etag, err := files.etag(name, content, modTime)
+	if err != nil {
+		return err
+	}
+
+	header.Set("ETag", etag)
+
+	http.ServeContent(w, r, name, modTime, content)
+
+	return nil
+}
+
+// gzipped - returns name of precompressed sibling of file if it exists.
+func (files *Files) gzipped(name string) (string, bool) {
+	info, err := fs.Stat(files.fsys, name+gzipSuffix)
+	if err != nil || info.IsDir() {
+		return "", false
+	}
+
+	return name + gzipSuffix, true
+}
+
+// open - returns seekable content of file and its modification time.
+func (files *Files) open(name string, info fs.FileInfo) (io.ReadSeekCloser, time.Time, error) {
+	file, err := files.fsys.Open(name)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+
+	if info == nil {
+		if info, err = file.Stat(); err != nil {
+			file.Close()
+			return nil, time.Time{}, err
+		}
+	}
+
+	if seeker, ok := file.(io.ReadSeekCloser); ok {
+		return seeker, info.ModTime(), nil
+	}
+
+	defer file.Close()
+
+	content, err := io.ReadAll(file)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+
+	return nopCloser{bytes.NewReader(content)}, info.ModTime(), nil
+}
+
+type nopCloser struct {
+	io.ReadSeeker
+}
+
+func (nopCloser) Close() error {
+	return nil
+}
+
+// etag - returns strong ETag of content, hash is cached until file's size or modification time change.
+func (files *Files) etag(name string, content io.ReadSeeker, modTime time.Time) (string, error) {
+	size, err := content.Seek(0, io.SeekEnd)
+	if err != nil {
+		return "", err
+	}
+
+	var key = fmt.Sprintf("%s:%d:%d", name, size, modTime.UnixNano())
+
+	if etag, ok := files.etags.Load(key); ok {
+		return etag.(string), rewind(content)
+	}
+
+	if err := rewind(content); err != nil {
+		return "", err
+	}
+
+	var hash = sha256.New()
+	if _, err := io.Copy(hash, content); err != nil {
+		return "", err
+	}
+
+	var etag = fmt.Sprintf(`"%s"`, hex.EncodeToString(hash.Sum(nil))[:32])
+
+	files.etags.Store(key, etag)
+
+	return etag, rewind(content)
+}
+
+// redirect - redirects to path keeping query.
+func redirect(w http.ResponseWriter, r *http.Request, path string) {
+	if r.URL.RawQuery != "" {
+		path += "?" + r.URL.RawQuery
+	}
+
+	http.Redirect(w, r, path, http.StatusMovedPermanently)
+}
+
+func rewind(content io.Seeker) error {
+	_, err := content.Seek(0, io.SeekStart)
+	return err
+}
+
+// acceptsGzip - checks if 'Accept-Encoding' header allows gzip.
+func acceptsGzip(header string) bool {
+	for _, encoding := range strings.Split(header, ",") {
+		name, params, _ := strings.Cut(strings.TrimSpace(encoding), ";")
+		if name = strings.TrimSpace(name); name != "gzip" && name != "*" {
+			continue
+		}
+
+		quality, ok := strings.CutPrefix(strings.ReplaceAll(params, " ", ""), "q=")
+		if !ok {
+			return true
+		}
+
+		value, err := strconv.ParseFloat(quality, 64)
+
+		return err == nil && value > 0
+	}
+
+	return false
+}
@@ -18,6 +18,11 @@ type (
 		// SetError - sets error response into object.
 		SetError(err error)
 	}
+	// DetailsSetter - optional interface of Responser supporting error details.
+	DetailsSetter interface {
+		// SetDetails - sets error details into object.
+		SetDetails(details interface{})
+	}
 )
 
 func NewJSONMarshaler() *Marshaler {
@@ -0,0 +1,111 @@
+package engi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+)
+
+type (
+	// Initializer - optional interface of ServiceAPI called on engine start before serving requests.
+	// Error returned by any service aborts engine start.
+	Initializer interface {
+		Init(ctx context.Context) error
+	}
+
+	// Starter - optional interface of ServiceAPI called on engine start after all services were initialized.
+	Starter interface {
+		Start(ctx context.Context) error
+	}
+
+	// Stopper - optional interface of ServiceAPI called on engine shutdown after requests were drained.
+	// Services are stopped in reverse registration order.
+	Stopper interface {
+		Stop(ctx context.Context) error
+	}
+)
+
+// component - service or nested service having lifecycle hooks.
+type component struct {
+	name   string
+	api    ServiceAPI
+	logger *slog.Logger
+}
+
+// components - returns services with their nested services in registration order.
+func components(services []*Service) []component {
+	var result = make([]component, 0, len(services))
+
+	for _, srv := range services {
+		result = append(result, srv.components...)
+	}
+
+	return result
+}
+
+// startServices - initializes and starts services in registration order.
+// If any of them fails, already started services are stopped.
+func startServices(ctx context.Context, services []*Service) error {
+	var (
+		errs       = make([]error, 0)
+		components = components(services)
+	)
+
+	for _, component := range components {
+		if initializer, ok := component.api.(Initializer); ok {
+			if err := initializer.Init(ctx); err != nil {
+				errs = append(errs, fmt.Errorf("init service '%s': %w", component.name, err))
+			}
+		}
+	}
+
+	if len(errs) != 0 {
+		return errors.Join(errs...)
+	}
+
+	for i, component := range components {
+		if starter, ok := component.api.(Starter); ok {
+			if err := starter.Start(ctx); err != nil {
+				return errors.Join(
+					fmt.Errorf("start service '%s': %w", component.name, err),
+					stopComponents(ctx, components[:i]),
+				)
+			}
+		}
+
+		component.logger.Debug("service started", slog.String("component", component.name))
+	}
+
+	return nil
+}
+
+// stopServices - stops services in reverse registration order.
+func stopServices(ctx context.Context, services []*Service) error {
+	return stopComponents(ctx, components(services))
+}
+
+func stopComponents(ctx context.Context, components []component) error {
+	var errs = make([]error, 0)
+
+	for i := len(components) - 1; i >= 0; i-- {
+		var component = components[i]
+
+		if stopper, ok := component.api.(Stopper); ok {
+			if err := stopper.Stop(ctx); err != nil {
+				component.logger.Error("stopping service failed",
+					slog.String("component", component.name),
+					slog.String("error", err.Error()),
+				)
+
+				errs = append(errs, fmt.Errorf("stop service '%s': %w", component.name, err))
+
+				continue
+			}
+		}
+
+		component.logger.Debug("service stopped", slog.String("component", component.name))
+	}
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,224 @@
+package engi_test
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"net"
+	"net/http"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/KlyuchnikovV/engi"
+)
+
+type stoppingAPI struct {
+	started chan struct{}
+	release chan struct{}
+	stopErr chan error
+}
+
+func (api *stoppingAPI) Prefix() string { return "slow" }
+
+func (api *stoppingAPI) Routers() engi.Routes {
+	return engi.Routes{
+		"wait": engi.GET(func(_ context.Context, _ engi.Request, response engi.Response) error {
+			close(api.started)
+			<-api.release
+
+			return response.OK("done")
+		}),
+	}
+}
+
+func (api *stoppingAPI) Stop(ctx context.Context) error {
+	api.stopErr <- ctx.Err()
+
+	return nil
+}
+
+// freeAddress - returns local address nobody listens on.
+func freeAddress(t *testing.T) string {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer listener.Close()
+
+	return listener.Addr().String()
+}
+
+func TestStopperGetsLiveContextAfterDrainDeadline(t *testing.T) {
+	var (
+		address = freeAddress(t)
+		api     = &stoppingAPI{
+			started: make(chan struct{}),
+			release: make(chan struct{}),
+			stopErr: make(chan error, 1),
+		}
+		e = engi.New(address, engi.WithShutdownTimeout(time.Second))
+	)
+
+	if err := e.RegisterServices(api); err != nil {
+		t.Fatal(err)
+	}
+
+	go e.Start() //nolint:errcheck
+
+	go func() {
+		for {
+			response, err := http.Get("http://" + address + "/slow/wait")
+			if err == nil {
+				response.Body.Close()
+				return
+			}
+
+			time.Sleep(10 * time.Millisecond)
+		}
+	}()
+
+	select {
+	case <-api.started:
+	case <-time.After(5 * time.Second):
+		t.Fatal("request was not started")
+	}
+
+	defer close(api.release)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	if err := e.Shutdown(ctx); !errors.Is(err, engi.ErrShutdownTimeout) {
+		t.Fatalf("expected '%s', got '%v'", engi.ErrShutdownTimeout, err)
+	}
+
+	if err := <-api.stopErr; err != nil {
+		t.Fatalf("stopper got expired context: %s", err)
+	}
+}
+
+type pingAPI struct{}
+
+func (pingAPI) Prefix() string { return "ping" }
+
+func (pingAPI) Routers() engi.Routes {
+	return engi.Routes{
+		"": engi.GET(func(_ context.Context, _ engi.Request, response engi.Response) error {
+			return response.OK("pong")
+		}),
+	}
+}
+
+func TestTLSListeners(t *testing.T) {
+	var (
+		addresses = []string{freeAddress(t), freeAddress(t)}
+		e         = engi.New(addresses[0], engi.WithSelfSignedTLS(), engi.WithListener("tcp", addresses[1]))
+		errs      = make(chan error, 1)
+		client    = &http.Client{Transport: &http.Transport{
+			TLSClientConfig: &tls.Config{InsecureSkipVerify: true}, //nolint:gosec
+		}}
+	)
+
+	if err := e.RegisterServices(pingAPI{}); err != nil {
+		t.Fatal(err)
+	}
+
+	go func() { errs <- e.Start() }()
+
+	defer func() {
+		if err := e.Shutdown(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := <-errs; !errors.Is(err, http.ErrServerClosed) {
+			t.Fatalf("expected '%s', got '%v'", http.ErrServerClosed, err)
+		}
+	}()
+
+	for _, address := range addresses {
+		var deadline = time.Now().Add(5 * time.Second)
+
+		for {
+			response, err := client.Get("https://" + address + "/ping")
+			if err == nil {
+				response.Body.Close()
+
+				if response.StatusCode != http.StatusOK {
+					t.Fatalf("%s: expected %d, got %d", address, http.StatusOK, response.StatusCode)
+				}
+
+				break
+			}
+
+			if time.Now().After(deadline) {
+				t.Fatalf("%s: %s", address, err)
+			}
+
+			time.Sleep(10 * time.Millisecond)
+		}
+	}
+}
+
+func TestStartAndWaitWithoutShutdownDeadline(t *testing.T) {
+	var (
+		address = freeAddress(t)
+		api     = &stoppingAPI{
+			started: make(chan struct{}),
+			release: make(chan struct{}),
+			stopErr: make(chan error, 1),
+		}
+		e         = engi.New(address, engi.WithShutdownTimeout(0))
+		stopped   = make(chan error, 1)
+		responded = make(chan int, 1)
+	)
+
+	if err := e.RegisterServices(api); err != nil {
+		t.Fatal(err)
+	}
+
+	go func() { stopped <- e.StartAndWait() }()
+
+	go func() {
+		for {
+			response, err := http.Get("http://" + address + "/slow/wait")
+			if err == nil {
+				response.Body.Close()
+				responded <- response.StatusCode
+
+				return
+			}
+
+			time.Sleep(10 * time.Millisecond)
+		}
+	}()
+
+	select {
+	case <-api.started:
+	case <-time.After(5 * time.Second):
+		t.Fatal("request was not started")
+	}
+
+	// Signal handler is set before engine starts serving requests.
+	if err := syscall.Kill(syscall.Getpid(), syscall.SIGTERM); err != nil {
+		t.Fatal(err)
+	}
+
+	time.Sleep(50 * time.Millisecond)
+	close(api.release)
+
+	if code := <-responded; code != http.StatusOK {
+		t.Fatalf("expected %d, got %d", http.StatusOK, code)
+	}
+
+	if err := <-stopped; err != nil {
+		t.Fatalf("expected requests to be drained, got '%s'", err)
+	}
+
+	if err := <-api.stopErr; err != nil {
+		t.Fatalf("stopper got expired context: %s", err)
+	}
+}
@@ -0,0 +1,112 @@
+package engi_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/KlyuchnikovV/engi"
+	"github.com/KlyuchnikovV/engi/parameter"
+	"github.com/KlyuchnikovV/engi/parameter/placing"
+	"github.com/KlyuchnikovV/engi/parameter/query"
+	"github.com/KlyuchnikovV/engi/validate"
+)
+
+type listAPI struct{}
+
+func (listAPI) Prefix() string { return "l" }
+
+func (listAPI) Routers() engi.Routes {
+	var echo = func(list func(engi.Request) interface{}) engi.Route {
+		return func(_ context.Context, request engi.Request, response engi.Response) error {
+			return response.OK(fmt.Sprint(list(request)))
+		}
+	}
+
+	return engi.Routes{
+		"ints": engi.GET(echo(func(request engi.Request) interface{} {
+			return request.Integers("id", placing.InQuery)
+		}), query.Integers("id", ",", validate.Each(validate.Greater(0)), validate.MaxItems(3), validate.Unique)),
+		"strings": engi.GET(echo(func(request engi.Request) interface{} {
+			return request.Strings("tag", placing.InQuery)
+		}), query.Strings("tag", ",|", validate.MinItems(2))),
+		"floats": engi.GET(echo(func(request engi.Request) interface{} {
+			return request.Floats("f", placing.InQuery)
+		}), query.Floats("f", "")),
+		"times": engi.GET(echo(func(request engi.Request) interface{} {
+			var days = request.Times("d", time.DateOnly, placing.InQuery)
+			if len(days) == 0 {
+				return ""
+			}
+
+			return days[len(days)-1].Format(time.DateOnly)
+		}), query.Times("d", time.DateOnly, ",", validate.Unique)),
+		"headers": engi.GET(echo(func(request engi.Request) interface{} {
+			return request.Strings("X-Tag", placing.InHeader)
+		}), parameter.Strings("X-Tag", ",", placing.InHeader)),
+		"optional": engi.GET(echo(func(request engi.Request) interface{} {
+			return request.Integers("id", placing.InQuery)
+		}), query.Integers("id", ",", parameter.Optional())),
+		"undeclared": engi.GET(echo(func(request engi.Request) interface{} {
+			return request.Integers("id", placing.InQuery)
+		})),
+	}
+}
+
+func TestListParameters(t *testing.T) {
+	var e = engi.New(":0")
+
+	if err := e.RegisterServices(listAPI{}); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, test := range []struct {
+		name    string
+		target  string
+		headers []string
+		code    int
+		body    string
+	}{
+		{"repeated and delimited", "/l/ints?id=1&id=2,3", nil, http.StatusOK, `"[1 2 3]"`},
+		{"spaces and empty elements", "/l/ints?id=1,%202,,", nil, http.StatusOK, `"[1 2]"`},
+		{"too many items", "/l/ints?id=1,2,3,4", nil, http.StatusBadRequest, ""},
+		{"invalid item", "/l/ints?id=1,0", nil, http.StatusBadRequest, ""},
+		{"repeated item", "/l/ints?id=1&id=1", nil, http.StatusBadRequest, ""},
+		{"not converted item", "/l/ints?id=1,a", nil, http.StatusBadRequest, ""},
+		{"missing", "/l/ints", nil, http.StatusBadRequest, ""},
+		{"several delimiters", "/l/strings?tag=a%7Cb,c", nil, http.StatusOK, `"[a b c]"`},
+		{"too few items", "/l/strings?tag=a", nil, http.StatusBadRequest, ""},
+		{"repeated without delimiters", "/l/floats?f=1.5&f=2", nil, http.StatusOK, `"[1.5 2]"`},
+		{"delimited without delimiters", "/l/floats?f=1.5,2", nil, http.StatusBadRequest, ""},
+		{"times", "/l/times?d=2024-01-02,2024-01-03", nil, http.StatusOK, `"2024-01-03"`},
+		{"repeated time", "/l/times?d=2024-01-02&d=2024-01-02", nil, http.StatusBadRequest, ""},
+		{"headers", "/l/headers", []string{"a, b", "c"}, http.StatusOK, `"[a b c]"`},
+		{"optional absent", "/l/optional", nil, http.StatusOK, `"[]"`},
+		{"optional present", "/l/optional?id=5", nil, http.StatusOK, `"[5]"`},
+		{"undeclared skips invalid", "/l/undeclared?id=1&id=a&id=2", nil, http.StatusOK, `"[1 2]"`},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			var (
+				recorder = httptest.NewRecorder()
+				request  = httptest.NewRequest(http.MethodGet, test.target, nil)
+			)
+
+			for _, header := range test.headers {
+				request.Header.Add("X-Tag", header)
+			}
+
+			e.ServeHTTP(recorder, request)
+
+			if recorder.Code != test.code {
+				t.Fatalf("expected %d, got %d (%s)", test.code, recorder.Code, recorder.Body)
+			}
+
+			if test.body != "" && recorder.Body.String() != test.body {
+				t.Fatalf("expected '%s', got '%s'", test.body, recorder.Body)
+			}
+		})
+	}
+}
@@ -0,0 +1,216 @@
+package engi
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/KlyuchnikovV/engi/internal/listen"
+)
+
+type (
+	// ListenerOption - configures additional listener.
+	ListenerOption func(*listener)
+
+	// listener - additional source of connections served by engine with its own server settings.
+	listener struct {
+		name      string
+		server    *http.Server
+		plainText bool
+		names     []string
+		listen    func(*listener) ([]net.Listener, error)
+		opts      []ListenerOption
+	}
+
+	// boundListener - opened listener with server serving it.
+	boundListener struct {
+		net.Listener
+		server *http.Server
+	}
+)
+
+func newListener(name string, listen func(*listener) ([]net.Listener, error), opts ...ListenerOption) *listener {
+	var l = &listener{
+		name:   name,
+		listen: listen,
+		opts:   opts,
+		server: &http.Server{
+			ReadTimeout:       defaultTimeout,
+			WriteTimeout:      defaultTimeout,
+			IdleTimeout:       defaultTimeout,
+			ReadHeaderTimeout: defaultTimeout,
+		},
+	}
+
+	for _, opt := range opts {
+		opt(l)
+	}
+
+	return l
+}
+
+// WithListener - additionally serves api on network address ("tcp", "tcp4", "tcp6" or "unix").
+func WithListener(network, address string, opts ...ListenerOption) Option {
+	return func(engine *Engine) {
+		engine.listeners = append(engine.listeners, newListener(
+			fmt.Sprintf("%s:%s", network, address),
+			func(*listener) ([]net.Listener, error) {
+				if network == "unix" {
+					return listen.Unix(address)
+				}
+
+				listener, err := net.Listen(network, address)
+				if err != nil {
+					return nil, err
+				}
+
+				return []net.Listener{listener}, nil
+			},
+			opts...,
+		))
+	}
+}
+
+// WithUnixSocket - additionally serves api on Unix domain socket, stale socket file is removed on start,
+// start fails if socket is used by running server.
+// Connections are served without TLS unless UseTLS listener option is passed.
+func WithUnixSocket(path string, opts ...ListenerOption) Option {
+	return WithListener("unix", path, append([]ListenerOption{PlainText}, opts...)...)
+}
+
+// WithSocketActivation - additionally serves api on sockets passed by systemd-style socket activation
+// ('LISTEN_FDS' environment variable). Sockets can be split between listeners using SocketNames option.
+//
+// If address passed to New is empty, api is served only on activated sockets.
+func WithSocketActivation(opts ...ListenerOption) Option {
+	return func(engine *Engine) {
+		engine.listeners = append(engine.listeners, newListener(
+			"activated",
+			func(l *listener) ([]net.Listener, error) {
+				return listen.Activated(l.names...)
+			},
+			opts...,
+		))
+	}
+}
+
+// SocketNames - serves only activated sockets with provided names ('LISTEN_FDNAMES' environment variable).
+func SocketNames(names ...string) ListenerOption {
+	return func(l *listener) {
+		l.names = names
+		l.name = fmt.Sprintf("activated:%v", names)
+	}
+}
+
+// ReadTimeout - sets listener's http.Server.ReadTimeout.
+func ReadTimeout(timeout time.Duration) ListenerOption {
+	return func(l *listener) {
+		l.server.ReadTimeout = timeout
+	}
+}
+
+// WriteTimeout - sets listener's http.Server.WriteTimeout.
+func WriteTimeout(timeout time.Duration) ListenerOption {
+	return func(l *listener) {
+		l.server.WriteTimeout = timeout
+	}
+}
+
+// IdleTimeout - sets listener's http.Server.IdleTimeout.
+func IdleTimeout(timeout time.Duration) ListenerOption {
+	return func(l *listener) {
+		l.server.IdleTimeout = timeout
+	}
+}
+
+// ReadHeaderTimeout - sets listener's http.Server.ReadHeaderTimeout.
+func ReadHeaderTimeout(timeout time.Duration) ListenerOption {
+	return func(l *listener) {
+		l.server.ReadHeaderTimeout = timeout
+	}
+}
+
+// PlainText - serves listener without TLS even if engine's TLS options are set.
+func PlainText(l *listener) {
+	l.plainText = true
+}
+
+// UseTLS - serves listener with engine's TLS configuration.
+func UseTLS(l *listener) {
+	l.plainText = false
+}
+
+// listen - opens all engine listeners, on error already opened listeners are closed.
+func (e *Engine) listen() ([]boundListener, error) {
+	var (
+		result    = make([]boundListener, 0, len(e.listeners)+1)
+		listeners = e.listeners
+	)
+
+	if e.server.Addr != "" {
+		listeners = append([]*listener{{
+			name:   fmt.Sprintf("tcp:%s", e.server.Addr),
+			server: e.server,
+			listen: func(*listener) ([]net.Listener, error) { return listen.TCP(e.server.Addr) },
+		}}, listeners...)
+	}
+
+	for _, l := range listeners {
+		opened, err := l.listen(l)
+		if err != nil {
+			for _, bound := range result {
+				bound.Close()
+			}
+
+			return nil, fmt.Errorf("listening on '%s' failed: %w", l.name, err)
+		}
+
+		if l.server != e.server {
+			l.server.Handler = e
+
+			for _, set := range e.serverTimeouts {
+				set(l.server)
+			}
+
+			// Listener's own options override engine's timeouts.
+			for _, opt := range l.opts {
+				opt(l)
+			}
+
+			if !l.plainText {
+				// Every server sets up HTTP/2 in its own TLS config.
+				l.server.TLSConfig = e.server.TLSConfig.Clone()
+			}
+		}
+
+		for _, netListener := range opened {
+			result = append(result, boundListener{Listener: netListener, server: l.server})
+		}
+	}
+
+	return result, nil
+}
+
+// serve - serves connections from listener, returns when server is closed.
+func (l boundListener) serve() error {
+	if l.server.TLSConfig != nil {
+		// Certificates are provided by TLS config.
+		return l.server.ServeTLS(l.Listener, "", "")
+	}
+
+	return l.server.Serve(l.Listener)
+}
+
+// servers - returns all servers used by engine.
+func (e *Engine) servers() []*http.Server {
+	var servers = make([]*http.Server, 0, len(e.listeners)+1)
+
+	servers = append(servers, e.server)
+
+	for _, l := range e.listeners {
+		servers = append(servers, l.server)
+	}
+
+	return servers
+}
@@ -0,0 +1,132 @@
+package engi
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/KlyuchnikovV/engi/internal/middlewares"
+	"github.com/KlyuchnikovV/engi/internal/pathfinder"
+)
+
+var (
+	ErrRouteNameNotFound          = fmt.Errorf("route with name not registered")
+	ErrRouteNameAlreadyRegistered = fmt.Errorf("route with same name already registered")
+)
+
+// P - values of path parameters used to build route's URL.
+type P map[string]interface{}
+
+// Name - names route so its URL can be built with Engine.URL.
+//
+//	"get/{id:int}": engi.GET(api.GetByID, engi.Name("notes.get")),
+func Name(name string) Register {
+	return func(middlewares *middlewares.Middlewares) {
+		middlewares.SetName(name)
+	}
+}
+
+// Links - declares names of routes the route builds URLs for.
+// Engine fails to start if any of them is not registered.
+func Links(names ...string) Register {
+	return func(middlewares *middlewares.Middlewares) {
+		middlewares.AddLinks(names...)
+	}
+}
+
+// URL - builds escaped path of named route including api's and service's prefixes.
+// Parameters are checked against route's constraints, optional parameters can be omitted.
+// If version is selected by path, URL of service declaring versions has version segment:
+// the default version if service serves it, otherwise the latest of service's versions.
+//
+//	w.URL("notes.get", engi.P{"id": 5}, url.Values{"fields": {"title"}}) // "/api/notes/get/5?fields=title"
+func (e *Engine) URL(name string, params P, query ...url.Values) (string, error) {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	srv, pattern, ok := findName(e.services, name)
+	if !ok {
+		return "", fmt.Errorf("%w: '%s'", ErrRouteNameNotFound, name)
+	}
+
+	var values = make(map[string]string, len(params))
+	for key, value := range params {
+		values[key] = formatParam(value)
+	}
+
+	path, err := pathfinder.Reverse(pattern, values)
+	if err != nil {
+		return "", fmt.Errorf("route '%s': %w", name, err)
+	}
+
+	var result = e.versionedPath(srv) + path
+
+	var encoded = make([]string, 0, len(query))
+	for _, values := range query {
+		if len(values) != 0 {
+			encoded = append(encoded, values.Encode())
+		}
+	}
+
+	if len(encoded) != 0 {
+		result = fmt.Sprintf("%s?%s", result, strings.Join(encoded, "&"))
+	}
+
+	return result, nil
+}
+
+// findName - returns service and pattern of route with name.
+func findName(services []*Service, name string) (*Service, string, bool) {
+	for _, srv := range services {
+		if pattern, ok := srv.names[name]; ok {
+			return srv, pattern, true
+		}
+	}
+
+	return nil, "", false
+}
+
+// checkNames - checks that route names are unique across services.
+func checkNames(services []*Service) error {
+	var names = make(map[string]string)
+
+	for _, srv := range services {
+		for name := range srv.names {
+			if prefix, ok := names[name]; ok {
+				return fmt.Errorf("%w: '%s' (services: '%s', '%s')", ErrRouteNameAlreadyRegistered, name, prefix, srv.Prefix())
+			}
+
+			names[name] = srv.Prefix()
+		}
+	}
+
+	return nil
+}
+
+// checkLinks - checks that routes declared with Links are registered.
+func checkLinks(services []*Service) error {
+	for _, srv := range services {
+		for name, pattern := range srv.links {
+			if _, _, ok := findName(services, name); !ok {
+				return fmt.Errorf("%w: '%s' (linked from '%s')", ErrRouteNameNotFound, name, pattern)
+			}
+		}
+	}
+
+	return nil
+}
+
+// formatParam - formats value of path parameter, dates are formatted as YYYY-MM-DD.
+func formatParam(value interface{}) string {
+	switch typed := value.(type) {
+	case string:
+		return typed
+	case time.Time:
+		return typed.Format(pathfinder.DateLayout)
+	case fmt.Stringer:
+		return typed.String()
+	default:
+		return fmt.Sprint(value)
+	}
+}
@@ -0,0 +1,188 @@
+package engi_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/KlyuchnikovV/engi"
+	"github.com/KlyuchnikovV/engi/internal/pathfinder"
+)
+
+type versionedAPI struct {
+	versions []string
+}
+
+func (versionedAPI) Prefix() string { return "notes" }
+
+func (api versionedAPI) Versions() []string { return api.versions }
+
+func (api versionedAPI) Routers() engi.Routes {
+	return engi.Routes{
+		"list": engi.GET(func(_ context.Context, _ engi.Request, response engi.Response) error {
+			return response.OK(api.versions)
+		}, engi.Name("notes.list."+api.versions[0])),
+	}
+}
+
+func TestURLHasPathVersion(t *testing.T) {
+	var e = engi.New(":0", engi.WithPrefix("api"), engi.WithVersioning(engi.VersionInPath(), engi.DefaultVersion("1")))
+
+	if err := e.RegisterServices(
+		versionedAPI{versions: []string{"1", "2"}},
+		versionedAPI{versions: []string{"3", "10"}},
+	); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, test := range []struct {
+		name     string
+		expected string
+	}{
+		{"notes.list.1", "/api/v1/notes/list"},
+		{"notes.list.3", "/api/v10/notes/list"},
+	} {
+		url, err := e.URL(test.name, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if url != test.expected {
+			t.Fatalf("%s: expected '%s', got '%s'", test.name, test.expected, url)
+		}
+
+		if recorder := serve(e, http.MethodGet, url); recorder.Code != http.StatusOK {
+			t.Fatalf("%s: expected %d, got %d (%s)", url, http.StatusOK, recorder.Code, recorder.Body)
+		}
+	}
+}
+
+func TestVersionsWithoutVersioning(t *testing.T) {
+	var e = engi.New(":0", engi.WithPrefix("api"))
+
+	if err := e.RegisterServices(versionedAPI{versions: []string{"1", "2"}}); err != nil {
+		t.Fatal(err)
+	}
+
+	url, err := e.URL("notes.list.1", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if url != "/api/notes/list" {
+		t.Fatalf("expected '/api/notes/list', got '%s'", url)
+	}
+
+	if recorder := serve(e, http.MethodGet, url); recorder.Code != http.StatusOK {
+		t.Fatalf("%s: expected %d, got %d (%s)", url, http.StatusOK, recorder.Code, recorder.Body)
+	}
+}
+
+type namedAPI struct{}
+
+func (namedAPI) Prefix() string { return "notes" }
+
+func (namedAPI) Routers() engi.Routes {
+	var ok = func(_ context.Context, _ engi.Request, response engi.Response) error {
+		return response.OK("ok")
+	}
+
+	return engi.Routes{
+		"get/{id:int}":       engi.GET(ok, engi.Name("notes.get")),
+		"by-title/{title}":   engi.GET(ok, engi.Name("notes.title")),
+		"day/{day:date}":     engi.GET(ok, engi.Name("notes.day")),
+		"docs/{page?}":       engi.GET(ok, engi.Name("notes.docs")),
+		"files/{path...}":    engi.GET(ok, engi.Name("notes.files")),
+		"create":             engi.POST(ok, engi.Name("notes.create"), engi.Links("notes.get")),
+		"{kind}/{id:uuid}/x": engi.GET(ok, engi.Name("notes.kind")),
+	}
+}
+
+func TestURL(t *testing.T) {
+	var e = engi.New(":0", engi.WithPrefix("api"))
+
+	if err := e.RegisterServices(namedAPI{}); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, test := range []struct {
+		name     string
+		params   engi.P
+		query    []url.Values
+		expected string
+		err      error
+	}{
+		{"notes.get", engi.P{"id": 5}, nil, "/api/notes/get/5", nil},
+		{"notes.get", engi.P{"id": 5}, []url.Values{{"fields": {"title", "body"}}, {"q": {"a b"}}}, "/api/notes/get/5?fields=title&fields=body&q=a+b", nil},
+		{"notes.title", engi.P{"title": "a/b c?"}, nil, "/api/notes/by-title/a%2Fb%20c%3F", nil},
+		{"notes.day", engi.P{"day": time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)}, nil, "/api/notes/day/2024-01-02", nil},
+		{"notes.docs", engi.P{"page": "intro"}, nil, "/api/notes/docs/intro", nil},
+		{"notes.docs", nil, nil, "/api/notes/docs", nil},
+		{"notes.files", engi.P{"path": "a b/c.txt"}, nil, "/api/notes/files/a%20b/c.txt", nil},
+		{"notes.get", engi.P{"id": "abc"}, nil, "", pathfinder.ErrInvalidParameter},
+		{"notes.kind", engi.P{"kind": "x", "id": "5"}, nil, "", pathfinder.ErrInvalidParameter},
+		{"notes.get", nil, nil, "", pathfinder.ErrMissingParameter},
+		{"notes.unknown", nil, nil, "", engi.ErrRouteNameNotFound},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			result, err := e.URL(test.name, test.params, test.query...)
+			if !errors.Is(err, test.err) {
+				t.Fatalf("expected error '%v', got '%v'", test.err, err)
+			}
+
+			if result != test.expected {
+				t.Fatalf("expected '%s', got '%s'", test.expected, result)
+			}
+
+			if result == "" {
+				return
+			}
+
+			// Built URL is routed to the named route.
+			if recorder := serve(e, http.MethodGet, result); recorder.Code != http.StatusOK {
+				t.Fatalf("%s: expected %d, got %d (%s)", result, http.StatusOK, recorder.Code, recorder.Body)
+			}
+		})
+	}
+}
+
+func TestLinksToUnknownRoute(t *testing.T) {
+	var e = engi.New(freeAddress(t))
+
+	if err := e.RegisterServices(routesAPI{"a", engi.Routes{
+		"x": engi.GET(nil, engi.Links("missing")),
+	}}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := e.Start(); !errors.Is(err, engi.ErrRouteNameNotFound) {
+		t.Fatalf("expected '%s', got '%v'", engi.ErrRouteNameNotFound, err)
+	}
+}
+
+func TestSameRouteName(t *testing.T) {
+	for _, test := range []struct {
+		name     string
+		services []engi.ServiceAPI
+	}{
+		{"same service", []engi.ServiceAPI{routesAPI{"a", engi.Routes{
+			"x": engi.GET(nil, engi.Name("x")),
+			"y": engi.GET(nil, engi.Name("x")),
+		}}}},
+		{"different services", []engi.ServiceAPI{
+			routesAPI{"a", engi.Routes{"x": engi.GET(nil, engi.Name("x"))}},
+			routesAPI{"b", engi.Routes{"x": engi.GET(nil, engi.Name("x"))}},
+		}},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			var err = engi.New(":0").RegisterServices(test.services...)
+
+			if !errors.Is(err, engi.ErrRouteNameAlreadyRegistered) {
+				t.Fatalf("expected '%s', got '%v'", engi.ErrRouteNameAlreadyRegistered, err)
+			}
+		})
+	}
+}
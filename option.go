@@ -5,6 +5,7 @@ import (
 	"log/slog"
 	"net/http"
 	"strings"
+	"time"
 
 	"github.com/KlyuchnikovV/engi/internal/types"
 	"github.com/KlyuchnikovV/engi/response"
@@ -64,3 +65,25 @@ func ResponseAsXML(object types.Responser) Option {
 		engine.responseMarshaler = *types.NewXMLMarshaler()
 	}
 }
+
+// WithShutdownTimeout - sets how long StartAndWait waits for in-flight requests on shutdown
+// and how long services implementing Stopper are given to stop after that. Zero timeout means no deadline.
+func WithShutdownTimeout(timeout time.Duration) Option {
+	return func(engine *Engine) {
+		engine.shutdownTimeout = timeout
+	}
+}
+
+// WithAddress - sets TCP network address overriding one passed to New.
+func WithAddress(address string) Option {
+	return func(engine *Engine) {
+		engine.server.Addr = address
+	}
+}
+
+// WithMiddlewares - sets middlewares applied to routes of every service before service's own middlewares.
+func WithMiddlewares(middlewares ...Register) Option {
+	return func(engine *Engine) {
+		engine.middlewares = append(engine.middlewares, middlewares...)
+	}
+}
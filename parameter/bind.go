@@ -0,0 +1,388 @@
+package parameter
+
+import (
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/KlyuchnikovV/engi/internal/middlewares"
+	"github.com/KlyuchnikovV/engi/internal/request"
+	"github.com/KlyuchnikovV/engi/parameter/placing"
+	"github.com/KlyuchnikovV/engi/response"
+)
+
+const (
+	tagDefault  = "default"
+	tagRequired = "required"
+	tagLayout   = "layout"
+
+	optionRequired = "required"
+)
+
+// places - struct tags of parameters in order they are looked up.
+var places = []placing.Placing{placing.InPath, placing.InQuery, placing.InHeader, placing.InCookie}
+
+var timeType = reflect.TypeOf(time.Time{})
+
+type (
+	// BindOption - configures binding of parameters into structure.
+	BindOption func(*binding)
+
+	binding struct {
+		validators map[string][]request.Option
+	}
+
+	// field - parameter bound into structure's field.
+	field struct {
+		index    []int
+		key      string
+		place    placing.Placing
+		required bool
+		fallback *string
+
+		pointer bool
+		slice   bool
+		scalar  reflect.Type
+		convert func(string) (interface{}, error)
+		opts    []request.Option
+	}
+)
+
+// Validate - sets validators of parameter bound into field, field is referenced by parameter's name or field's name.
+// Slice fields are checked as a whole (e.g. 'validate.MinItems'), their elements are checked with 'validate.Each'.
+//
+//	parameter.Bind[FilterParams](parameter.Validate("int", validate.Greater(1)))
+func Validate(name string, opts ...request.Option) BindOption {
+	return func(binding *binding) {
+		binding.validators[name] = append(binding.validators[name], opts...)
+	}
+}
+
+// Bind - binds request's parameters into new structure of type T for every request.
+// Fields are bound by struct tags 'path', 'query', 'header' or 'cookie' holding parameter's name:
+//
+//	type FilterParams struct {
+//		Int   int64     `query:"int,required"`
+//		Limit int       `query:"limit" default:"10"`
+//		Trace string    `header:"X-Trace"`
+//		Since time.Time `query:"since" layout:"2006-01-02"`
+//		Tags  []string  `query:"tag"`
+//		Flag  *bool     `cookie:"flag"`
+//	}
+//
+// Parameter is required if tag has 'required' option or field has 'required:"true"' tag, missing parameter
+// gets value of 'default' tag, otherwise field is left zero (nil for pointers).
+// Fields can be strings, booleans, integers, floats, time.Time ('layout' tag, RFC 3339 by default),
+// pointers to or slices of them. All binding errors are responded at once with 400 code.
+//
+// Result can be retrieved with 'engi.Params[T](request)'.
+func Bind[T any](opts ...BindOption) func(middlewares *middlewares.Middlewares) {
+	var binding = binding{validators: make(map[string][]request.Option)}
+	for _, opt := range opts {
+		opt(&binding)
+	}
+
+	var fields = binding.fields(reflect.TypeOf((*T)(nil)).Elem(), nil)
+
+	return func(middlewares *middlewares.Middlewares) {
+		middlewares.AddParams(func(r *request.Request, _ http.ResponseWriter) *response.AsObject {
+			var (
+				result = new(T)
+				value  = reflect.ValueOf(result).Elem()
+				errs   = make([]string, 0)
+			)
+
+			for _, field := range fields {
+				if err := field.bind(r, value.FieldByIndex(field.index)); err != nil {
+					errs = append(errs, err.Error())
+				}
+			}
+
+			if len(errs) != 0 {
+				var err = response.AsError(http.StatusBadRequest, "binding parameters failed: %s", strings.Join(errs, "; "))
+				err.SetDetails(errs)
+
+				return err
+			}
+
+			r.SetBound(*result)
+
+			return nil
+		})
+	}
+}
+
+// fields - returns bound fields of structure including embedded ones.
+// Panics if structure has field of unsupported type, so misconfiguration is found on registration.
+func (binding binding) fields(typ reflect.Type, index []int) []field {
+	if typ.Kind() != reflect.Struct {
+		panic(fmt.Errorf("binding parameters: '%s' is not a structure", typ))
+	}
+
+	var result = make([]field, 0, typ.NumField())
+
+	for i := 0; i < typ.NumField(); i++ {
+		var (
+			structField = typ.Field(i)
+			fieldIndex  = append(index[:len(index):len(index)], i)
+		)
+
+		if !structField.IsExported() {
+			continue
+		}
+
+		bound, ok := binding.field(structField, fieldIndex)
+		if ok {
+			result = append(result, bound)
+			continue
+		}
+
+		if structField.Anonymous && structField.Type.Kind() == reflect.Struct {
+			result = append(result, binding.fields(structField.Type, fieldIndex)...)
+		}
+	}
+
+	return result
+}
+
+// field - returns bound field or false if field has no parameter tag.
+func (binding binding) field(structField reflect.StructField, index []int) (field, bool) {
+	var result = field{index: index}
+
+	for _, place := range places {
+		tag, ok := structField.Tag.Lookup(string(place))
+		if !ok {
+			continue
+		}
+
+		var options []string
+
+		result.key, result.place = tag, place
+		if key, rest, ok := strings.Cut(tag, ","); ok {
+			result.key, options = key, strings.Split(rest, ",")
+		}
+
+		if result.key == "" {
+			result.key = structField.Name
+		}
+
+		if place == placing.InHeader {
+			result.key = http.CanonicalHeaderKey(result.key)
+		}
+
+		for _, option := range options {
+			result.required = result.required || strings.TrimSpace(option) == optionRequired
+		}
+
+		break
+	}
+
+	if result.place == "" {
+		return field{}, false
+	}
+
+	if required, ok := structField.Tag.Lookup(tagRequired); ok {
+		result.required = result.required || required == "" || required == "true"
+	}
+
+	if fallback, ok := structField.Tag.Lookup(tagDefault); ok {
+		result.fallback = &fallback
+	}
+
+	result.scalar = structField.Type
+	switch result.scalar.Kind() {
+	case reflect.Pointer:
+		result.pointer, result.scalar = true, result.scalar.Elem()
+	case reflect.Slice:
+		result.slice, result.scalar = true, result.scalar.Elem()
+	}
+
+	var layout = structField.Tag.Get(tagLayout)
+	if layout == "" {
+		layout = time.RFC3339
+	}
+
+	result.convert = converter(result.key, result.scalar, layout)
+	if result.convert == nil {
+		panic(fmt.Errorf("binding parameters: field '%s' has unsupported type '%s'", structField.Name, structField.Type))
+	}
+
+	result.opts = append(make([]request.Option, 0), binding.validators[result.key]...)
+	if structField.Name != result.key {
+		result.opts = append(result.opts, binding.validators[structField.Name]...)
+	}
+
+	return result, true
+}
+
+// converter - returns existing converter of parameter's type or nil if type is not supported.
+func converter(key string, typ reflect.Type, layout string) func(string) (interface{}, error) {
+	if typ == timeType {
+		return toTime(key, layout)
+	}
+
+	switch typ.Kind() {
+	case reflect.Bool:
+		return toBool
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return toInteger(key)
+	case reflect.Float32, reflect.Float64:
+		return toFloat(key)
+	case reflect.String:
+		return toString
+	default:
+		return nil
+	}
+}
+
+// bind - converts, validates and sets parameter into field.
+func (f field) bind(r *request.Request, value reflect.Value) error {
+	var values = f.values(r)
+
+	if len(values) == 0 {
+		switch {
+		case f.fallback != nil:
+			values = []string{*f.fallback}
+		case f.required:
+			return fmt.Errorf("parameter '%s' not found in %s", f.key, f.place)
+		default:
+			return nil
+		}
+	}
+
+	if f.slice {
+		var (
+			slice  = reflect.MakeSlice(value.Type(), len(values), len(values))
+			parsed reflect.Value
+		)
+
+		for i, raw := range values {
+			converted, err := f.convert(raw)
+			if err != nil {
+				return f.conversionError(err)
+			}
+
+			if err := f.set(raw, converted, slice.Index(i)); err != nil {
+				return err
+			}
+
+			if i == 0 {
+				parsed = reflect.MakeSlice(reflect.SliceOf(reflect.TypeOf(converted)), 0, len(values))
+			}
+
+			parsed = reflect.Append(parsed, reflect.ValueOf(converted))
+		}
+
+		// Slice is checked as a whole like list parameters, so its elements are checked with 'validate.Each'.
+		if err := f.validate(parsed.Interface()); err != nil {
+			return err
+		}
+
+		value.Set(slice)
+
+		return nil
+	}
+
+	converted, err := f.convert(values[0])
+	if err != nil {
+		return f.conversionError(err)
+	}
+
+	if err := f.validate(converted); err != nil {
+		return err
+	}
+
+	if f.pointer {
+		var pointer = reflect.New(f.scalar)
+		if err := f.set(values[0], converted, pointer.Elem()); err != nil {
+			return err
+		}
+
+		value.Set(pointer)
+
+		return nil
+	}
+
+	return f.set(values[0], converted, value)
+}
+
+// conversionError - names parameter which value wasn't converted, so errors responded at once can be told apart.
+func (f field) conversionError(err error) error {
+	return fmt.Errorf("parameter '%s' in %s: %w", f.key, f.place, err)
+}
+
+// values - returns raw values of parameter, repeated query parameters and headers give several values.
+func (f field) values(r *request.Request) []string {
+	var values []string
+
+	switch {
+	case f.slice && f.place == placing.InQuery:
+		values = r.GetRequest().URL.Query()[f.key]
+	case f.slice && f.place == placing.InHeader:
+		values = r.GetRequest().Header.Values(f.key)
+	default:
+		if value := r.GetParameter(f.key, f.place); value != "" {
+			values = []string{value}
+		}
+	}
+
+	return values
+}
+
+// validate - checks converted value of parameter with validators of field.
+func (f field) validate(parsed interface{}) error {
+	var parameter = request.Parameter{Name: f.key, Parsed: parsed}
+
+	for _, opt := range f.opts {
+		if err := opt.Validate(&parameter); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// set - sets value converted from raw one into field's value checking its range.
+func (f field) set(raw string, parsed interface{}, value reflect.Value) error {
+	switch typed := parsed.(type) {
+	case int64:
+		return setInteger(f.key, typed, value)
+	case float64:
+		if value.OverflowFloat(typed) {
+			return fmt.Errorf("parameter '%s' overflows %s (got: '%s')", f.key, value.Type(), raw)
+		}
+
+		value.SetFloat(typed)
+	case bool:
+		value.SetBool(typed)
+	case string:
+		value.SetString(typed)
+	default:
+		value.Set(reflect.ValueOf(parsed))
+	}
+
+	return nil
+}
+
+// setInteger - sets integer into signed or unsigned value checking its range.
+func setInteger(key string, parsed int64, value reflect.Value) error {
+	switch value.Kind() {
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if parsed < 0 || value.OverflowUint(uint64(parsed)) {
+			return fmt.Errorf("parameter '%s' is out of %s range (got: %d)", key, value.Type(), parsed)
+		}
+
+		value.SetUint(uint64(parsed))
+	default:
+		if value.OverflowInt(parsed) {
+			return fmt.Errorf("parameter '%s' is out of %s range (got: %d)", key, value.Type(), parsed)
+		}
+
+		value.SetInt(parsed)
+	}
+
+	return nil
+}
@@ -2,6 +2,7 @@ package parameter
 
 import (
 	"net/http"
+	"reflect"
 
 	"github.com/KlyuchnikovV/engi/internal/middlewares"
 	"github.com/KlyuchnikovV/engi/internal/request"
@@ -9,9 +10,43 @@ import (
 	"github.com/KlyuchnikovV/engi/response"
 )
 
-// Body - takes pointer to structure and saves casted request body into context and pointer.
+// BodyOf - unmarshals request body into new value of type T allocated for every request
+// according to request's content type.
+//
+// Result can be retrieved with 'engi.BodyAs[T](request)' or 'request.Body()' as '*T'.
+func BodyOf[T any](opts ...request.Option) func(middlewares *middlewares.Middlewares) {
+	return func(middlewares *middlewares.Middlewares) {
+		middlewares.AddParams(func(r *request.Request, _ http.ResponseWriter) *response.AsObject {
+			unmarshaler, err := request.GetUnmarshaler(r)
+			if err != nil {
+				return response.AsError(http.StatusInternalServerError, err.Error())
+			}
+
+			return request.ExtractBody(r, unmarshaler, new(T), opts)
+		})
+	}
+}
+
+// CustomBodyOf - unmarshals request body into new value of type T allocated for every request using unmarshaler.
+//
+// Result can be retrieved with 'engi.BodyAs[T](request)' or 'request.Body()' as '*T'.
+func CustomBodyOf[T any](
+	unmarshaler types.Unmarshaler,
+	opts ...request.Option,
+) func(middlewares *middlewares.Middlewares) {
+	return func(middlewares *middlewares.Middlewares) {
+		middlewares.AddParams(func(r *request.Request, _ http.ResponseWriter) *response.AsObject {
+			return request.ExtractBody(r, unmarshaler, new(T), opts)
+		})
+	}
+}
+
+// Body - takes pointer to structure and saves casted request body into context.
+// Pointer is used only as a template: body of every request is unmarshaled into new value of the same type.
 //
 // Result can be retrieved from context using 'context.QueryParams.Body'.
+//
+// Deprecated: use BodyOf, e.g. 'parameter.BodyOf[entity.NotesRequest]()'.
 func Body(pointer interface{}, opts ...request.Option) func(middlewares *middlewares.Middlewares) {
 	return func(middlewares *middlewares.Middlewares) {
 		middlewares.AddParams(func(r *request.Request, _ http.ResponseWriter) *response.AsObject {
@@ -20,14 +55,17 @@ func Body(pointer interface{}, opts ...request.Option) func(middlewares *middlew
 				return response.AsError(http.StatusInternalServerError, err.Error())
 			}
 
-			return request.ExtractBody(r, unmarshaler, pointer, opts)
+			return request.ExtractBody(r, unmarshaler, fresh(pointer), opts)
 		})
 	}
 }
 
 // CustomBody - takes unmarshaler and pointer to structure and saves casted request body into context.
+// Pointer is used only as a template: body of every request is unmarshaled into new value of the same type.
 //
 // Result can be retrieved from context using 'context.QueryParams.Body'.
+//
+// Deprecated: use CustomBodyOf, e.g. 'parameter.CustomBodyOf[entity.NotesRequest](unmarshaler)'.
 func CustomBody(
 	unmarshaler types.Unmarshaler,
 	pointer interface{},
@@ -35,7 +73,18 @@ func CustomBody(
 ) func(middlewares *middlewares.Middlewares) {
 	return func(middlewares *middlewares.Middlewares) {
 		middlewares.AddParams(func(r *request.Request, _ http.ResponseWriter) *response.AsObject {
-			return request.ExtractBody(r, unmarshaler, pointer, opts)
+			return request.ExtractBody(r, unmarshaler, fresh(pointer), opts)
 		})
 	}
 }
+
+// fresh - returns pointer to new zero value of type pointer points to,
+// so concurrent requests don't share it. Non-pointer values are returned as is.
+func fresh(pointer interface{}) interface{} {
+	var value = reflect.ValueOf(pointer)
+	if value.Kind() != reflect.Pointer || value.IsNil() {
+		return pointer
+	}
+
+	return reflect.New(value.Elem().Type()).Interface()
+}
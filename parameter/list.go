@@ -0,0 +1,90 @@
+package parameter
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/KlyuchnikovV/engi/internal/middlewares"
+	"github.com/KlyuchnikovV/engi/internal/request"
+	"github.com/KlyuchnikovV/engi/parameter/placing"
+	"github.com/KlyuchnikovV/engi/response"
+)
+
+// Integers - mandatory list of integers Parameter from request by 'key'.
+// Values of repeated 'key' are joined and each of them is split by any of 'delimiters' runes
+// (empty 'delimiters' only joins repeated values): '?id=1&id=2' or '?ids=1,2,3' with ",".
+//
+// Result can be retrieved from context using 'context.QueryParams.Integers'.
+func Integers(key, delimiters string, place placing.Placing, opts ...request.Option) func(middlewares *middlewares.Middlewares) {
+	var presence, checks = request.PresenceOf(key, opts, []int64(nil))
+
+	return func(middlewares *middlewares.Middlewares) {
+		middlewares.AddParams(func(r *request.Request, _ http.ResponseWriter) *response.AsObject {
+			return request.ExtractList(key, place, r, delimiters, presence, checks, toList[int64](toInteger(key)))
+		})
+	}
+}
+
+// Floats - mandatory list of floating point numbers Parameter from request by 'key'.
+// Values of repeated 'key' are joined and each of them is split by any of 'delimiters' runes.
+//
+// Result can be retrieved from context using 'context.QueryParams.Floats'.
+func Floats(key, delimiters string, place placing.Placing, opts ...request.Option) func(middlewares *middlewares.Middlewares) {
+	var presence, checks = request.PresenceOf(key, opts, []float64(nil))
+
+	return func(middlewares *middlewares.Middlewares) {
+		middlewares.AddParams(func(r *request.Request, _ http.ResponseWriter) *response.AsObject {
+			return request.ExtractList(key, place, r, delimiters, presence, checks, toList[float64](toFloat(key)))
+		})
+	}
+}
+
+// Strings - mandatory list of strings Parameter from request by 'key'.
+// Values of repeated 'key' are joined and each of them is split by any of 'delimiters' runes.
+//
+// Result can be retrieved from context using 'context.QueryParams.Strings'.
+func Strings(key, delimiters string, place placing.Placing, opts ...request.Option) func(middlewares *middlewares.Middlewares) {
+	var presence, checks = request.PresenceOf(key, opts, []string(nil))
+
+	return func(middlewares *middlewares.Middlewares) {
+		middlewares.AddParams(func(r *request.Request, _ http.ResponseWriter) *response.AsObject {
+			return request.ExtractList(key, place, r, delimiters, presence, checks, toList[string](toString))
+		})
+	}
+}
+
+// Times - mandatory list of time Parameter from request by 'key' using 'layout'.
+// Values of repeated 'key' are joined and each of them is split by any of 'delimiters' runes.
+//
+// Result can be retrieved from context using 'context.QueryParams.Times'.
+func Times(
+	key, layout, delimiters string,
+	place placing.Placing,
+	opts ...request.Option,
+) func(middlewares *middlewares.Middlewares) {
+	var presence, checks = request.PresenceOf(key, opts, []time.Time(nil))
+
+	return func(middlewares *middlewares.Middlewares) {
+		middlewares.AddParams(func(r *request.Request, _ http.ResponseWriter) *response.AsObject {
+			return request.ExtractList(key, place, r, delimiters, presence, checks, toList[time.Time](toTime(key, layout)))
+		})
+	}
+}
+
+// toList - converts every element of list parameter with 'convert' into slice of T.
+func toList[T any](convert func(string) (interface{}, error)) func([]string) (interface{}, error) {
+	return func(values []string) (interface{}, error) {
+		var result = make([]T, 0, len(values))
+
+		for _, value := range values {
+			converted, err := convert(value)
+			if err != nil {
+				return nil, err
+			}
+
+			result = append(result, converted.(T))
+		}
+
+		return result, nil
+	}
+}
@@ -11,17 +11,32 @@ import (
 	"github.com/KlyuchnikovV/engi/response"
 )
 
+// Optional - allows parameter to be missing in request, checks are applied only to passed values.
+// Missing parameter can be told from zero one with accessors like 'request.IntegerOK'.
+// Presence options are taken by parameter declarations only, they are ignored inside of 'validate.AND' or 'validate.OR'.
+//
+//	query.Integer("limit", parameter.Optional(), validate.Greater(0))
+func Optional() request.Presence {
+	return request.Optional()
+}
+
+// Default - allows parameter to be missing in request and sets 'value' converted to parameter's type instead.
+// Default value is not checked, panics on registration if 'value' can't be converted.
+//
+//	query.Integer("limit", parameter.Default(10), validate.Greater(0))
+func Default(value interface{}) request.Presence {
+	return request.Default(value)
+}
+
 // Bool - mandatory boolean Parameter from request by 'key'.
 //
 // Result can be retrieved from context using 'context.QueryParams.Bool'.
 func Bool(key string, place placing.Placing, opts ...request.Option) func(middlewares *middlewares.Middlewares) {
+	var presence, checks = request.PresenceOf(key, opts, false)
+
 	return func(middlewares *middlewares.Middlewares) {
 		middlewares.AddParams(func(r *request.Request, _ http.ResponseWriter) *response.AsObject {
-			return request.ExtractParam(key, place, r, opts,
-				func(request string) (interface{}, error) {
-					return strconv.ParseBool(request)
-				},
-			)
+			return request.ExtractParam(key, place, r, presence, checks, toBool)
 		})
 	}
 }
@@ -30,18 +45,11 @@ func Bool(key string, place placing.Placing, opts ...request.Option) func(middle
 //
 // Result can be retrieved from context using 'context.QueryParams.Integer'.
 func Integer(key string, place placing.Placing, opts ...request.Option) func(middlewares *middlewares.Middlewares) {
+	var presence, checks = request.PresenceOf(key, opts, int64(0))
+
 	return func(middlewares *middlewares.Middlewares) {
 		middlewares.AddParams(func(r *request.Request, _ http.ResponseWriter) *response.AsObject {
-			return request.ExtractParam(key, place, r, opts,
-				func(p string) (interface{}, error) {
-					result, err := strconv.ParseInt(p, request.IntBase, request.BitSize)
-					if err != nil {
-						return nil, response.AsError(http.StatusBadRequest, "Parameter '%s' not of type int (got: '%s')", key, p)
-					}
-
-					return result, err
-				},
-			)
+			return request.ExtractParam(key, place, r, presence, checks, toInteger(key))
 		})
 	}
 }
@@ -50,18 +58,11 @@ func Integer(key string, place placing.Placing, opts ...request.Option) func(mid
 //
 // Result can be retrieved from context using 'context.QueryParams.Float'.
 func Float(key string, place placing.Placing, opts ...request.Option) func(middlewares *middlewares.Middlewares) {
+	var presence, checks = request.PresenceOf(key, opts, float64(0))
+
 	return func(middlewares *middlewares.Middlewares) {
 		middlewares.AddParams(func(r *request.Request, _ http.ResponseWriter) *response.AsObject {
-			return request.ExtractParam(key, place, r, opts,
-				func(p string) (interface{}, error) {
-					result, err := strconv.ParseFloat(p, request.BitSize)
-					if err != nil {
-						return nil, response.AsError(http.StatusBadRequest, "Parameter '%s' not of type float (got: '%s')", key, p)
-					}
-
-					return result, err
-				},
-			)
+			return request.ExtractParam(key, place, r, presence, checks, toFloat(key))
 		})
 	}
 }
@@ -70,13 +71,11 @@ func Float(key string, place placing.Placing, opts ...request.Option) func(middl
 //
 // Result can be retrieved from context using 'context.QueryParams.String'.
 func String(key string, place placing.Placing, opts ...request.Option) func(middlewares *middlewares.Middlewares) {
+	var presence, checks = request.PresenceOf(key, opts, "")
+
 	return func(middlewares *middlewares.Middlewares) {
 		middlewares.AddParams(func(r *request.Request, _ http.ResponseWriter) *response.AsObject {
-			return request.ExtractParam(key, place, r, opts,
-				func(request string) (interface{}, error) {
-					return request, nil
-				},
-			)
+			return request.ExtractParam(key, place, r, presence, checks, toString)
 		})
 	}
 }
@@ -85,20 +84,59 @@ func String(key string, place placing.Placing, opts ...request.Option) func(midd
 //
 // Result can be retrieved from context using 'context.QueryParams.Time'.
 func Time(key, layout string, place placing.Placing, opts ...request.Option) func(middlewares *middlewares.Middlewares) {
+	var presence, checks = request.PresenceOf(key, opts, time.Time{})
+
 	return func(middlewares *middlewares.Middlewares) {
 		middlewares.AddParams(func(r *request.Request, _ http.ResponseWriter) *response.AsObject {
-			return request.ExtractParam(key, place, r, opts,
-				func(request string) (interface{}, error) {
-					result, err := time.Parse(layout, request)
-					if err != nil {
-						return nil, response.AsError(http.StatusBadRequest,
-							"could not parse '%s' request to datetime using '%s' layout", key, layout,
-						)
-					}
-
-					return result, err
-				},
-			)
+			return request.ExtractParam(key, place, r, presence, checks, toTime(key, layout))
 		})
 	}
 }
+
+// toBool - converts parameter to bool.
+func toBool(value string) (interface{}, error) {
+	return strconv.ParseBool(value)
+}
+
+// toInteger - converts parameter 'key' to int64.
+func toInteger(key string) func(string) (interface{}, error) {
+	return func(value string) (interface{}, error) {
+		result, err := strconv.ParseInt(value, request.IntBase, request.BitSize)
+		if err != nil {
+			return nil, response.AsError(http.StatusBadRequest, "Parameter '%s' not of type int (got: '%s')", key, value)
+		}
+
+		return result, err
+	}
+}
+
+// toFloat - converts parameter 'key' to float64.
+func toFloat(key string) func(string) (interface{}, error) {
+	return func(value string) (interface{}, error) {
+		result, err := strconv.ParseFloat(value, request.BitSize)
+		if err != nil {
+			return nil, response.AsError(http.StatusBadRequest, "Parameter '%s' not of type float (got: '%s')", key, value)
+		}
+
+		return result, err
+	}
+}
+
+// toString - returns parameter as is.
+func toString(value string) (interface{}, error) {
+	return value, nil
+}
+
+// toTime - converts parameter 'key' to time.Time using 'layout'.
+func toTime(key, layout string) func(string) (interface{}, error) {
+	return func(value string) (interface{}, error) {
+		result, err := time.Parse(layout, value)
+		if err != nil {
+			return nil, response.AsError(http.StatusBadRequest,
+				"could not parse '%s' request to datetime using '%s' layout", key, layout,
+			)
+		}
+
+		return result, err
+	}
+}
@@ -7,4 +7,5 @@ const (
 	InQuery  Placing = "query"
 	InCookie Placing = "cookie"
 	InHeader Placing = "header"
+	InHost   Placing = "host"
 )
@@ -41,3 +41,35 @@ func String(key string, opts ...request.Option) func(middlewares *middlewares.Mi
 func Time(key, layout string, opts ...request.Option) func(middlewares *middlewares.Middlewares) {
 	return parameter.Time(key, layout, placing.InQuery, opts...)
 }
+
+// Integers - mandatory list of integers Parameter from request by 'key'.
+// Values of repeated 'key' are joined and each of them is split by any of 'delimiters' runes.
+//
+// Result can be retrieved from context using 'context.QueryParams.Integers'.
+func Integers(key, delimiters string, opts ...request.Option) func(middlewares *middlewares.Middlewares) {
+	return parameter.Integers(key, delimiters, placing.InQuery, opts...)
+}
+
+// Floats - mandatory list of floating point numbers Parameter from request by 'key'.
+// Values of repeated 'key' are joined and each of them is split by any of 'delimiters' runes.
+//
+// Result can be retrieved from context using 'context.QueryParams.Floats'.
+func Floats(key, delimiters string, opts ...request.Option) func(middlewares *middlewares.Middlewares) {
+	return parameter.Floats(key, delimiters, placing.InQuery, opts...)
+}
+
+// Strings - mandatory list of strings Parameter from request by 'key'.
+// Values of repeated 'key' are joined and each of them is split by any of 'delimiters' runes.
+//
+// Result can be retrieved from context using 'context.QueryParams.Strings'.
+func Strings(key, delimiters string, opts ...request.Option) func(middlewares *middlewares.Middlewares) {
+	return parameter.Strings(key, delimiters, placing.InQuery, opts...)
+}
+
+// Times - mandatory list of time Parameter from request by 'key' using 'layout'.
+// Values of repeated 'key' are joined and each of them is split by any of 'delimiters' runes.
+//
+// Result can be retrieved from context using 'context.QueryParams.Times'.
+func Times(key, layout, delimiters string, opts ...request.Option) func(middlewares *middlewares.Middlewares) {
+	return parameter.Times(key, layout, delimiters, placing.InQuery, opts...)
+}
@@ -0,0 +1,149 @@
+package engi_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/KlyuchnikovV/engi"
+	"github.com/KlyuchnikovV/engi/internal/request"
+	"github.com/KlyuchnikovV/engi/parameter"
+	"github.com/KlyuchnikovV/engi/parameter/placing"
+	"github.com/KlyuchnikovV/engi/parameter/query"
+	"github.com/KlyuchnikovV/engi/validate"
+)
+
+// counter - validator counting values it checked.
+type counter int
+
+func (c *counter) Validate(*request.Parameter) error {
+	*c++
+	return nil
+}
+
+type optionalAPI struct {
+	checked counter
+}
+
+func (api *optionalAPI) Prefix() string { return "o" }
+
+func (api *optionalAPI) Routers() engi.Routes {
+	return engi.Routes{
+		"get": engi.GET(func(_ context.Context, request engi.Request, response engi.Response) error {
+			limit, limitOK := request.IntegerOK("limit", placing.InQuery)
+			offset, offsetOK := request.IntegerOK("offset", placing.InQuery)
+
+			return response.OK(fmt.Sprintf("%d %t %d %t", limit, limitOK, offset, offsetOK))
+		},
+			query.Integer("limit", parameter.Default(10), &api.checked, validate.Greater(0)),
+			query.Integer("offset", parameter.Optional(), validate.Greater(-1)),
+		),
+	}
+}
+
+func TestOptionalParameters(t *testing.T) {
+	var (
+		api = new(optionalAPI)
+		e   = engi.New(":0")
+	)
+
+	if err := e.RegisterServices(api); err != nil {
+		t.Fatal(err)
+	}
+
+	if api.checked != 0 {
+		t.Fatalf("validators were called %d times on registration", api.checked)
+	}
+
+	serveAll(t, e, []served{
+		{"/o/get", http.StatusOK, `"10 false 0 false"`},
+		{"/o/get?limit=5&offset=0", http.StatusOK, `"5 true 0 true"`},
+		{"/o/get?limit=0", http.StatusBadRequest, ""},
+		{"/o/get?offset=-1", http.StatusBadRequest, ""},
+	})
+
+	if api.checked != 2 {
+		t.Fatalf("expected validator to be called for 2 passed values, got %d", api.checked)
+	}
+}
+
+type bindParams struct {
+	IDs   []int64     `query:"id"`
+	Name  string      `query:"Name"`
+	Limit int         `query:"limit,required" default:"10"`
+	Flag  *bool       `query:"flag"`
+	Since *time.Time  `query:"since" layout:"2006-01-02"`
+	Days  []time.Time `query:"day" layout:"2006-01-02"`
+}
+
+type bindAPI struct {
+	checked counter
+}
+
+func (api *bindAPI) Prefix() string { return "b" }
+
+func (api *bindAPI) Routers() engi.Routes {
+	return engi.Routes{
+		"get": engi.GET(func(_ context.Context, request engi.Request, response engi.Response) error {
+			params, _ := engi.Params[bindParams](request)
+
+			var flag, since = "-", "-"
+			if params.Flag != nil {
+				flag = fmt.Sprint(*params.Flag)
+			}
+
+			if params.Since != nil {
+				since = params.Since.Format(time.DateOnly)
+			}
+
+			return response.OK(fmt.Sprintf("%v %s %d %s %s %d",
+				params.IDs, params.Name, params.Limit, flag, since, len(params.Days),
+			))
+		},
+			parameter.Bind[bindParams](
+				parameter.Validate("id", validate.MinItems(2), validate.Unique, validate.Each(validate.Greater(0))),
+				parameter.Validate("day", validate.MaxItems(2)),
+				parameter.Validate("Name", &api.checked),
+				parameter.Validate("limit", validate.Greater(0)),
+			),
+		),
+	}
+}
+
+func TestBindParameters(t *testing.T) {
+	var (
+		api = new(bindAPI)
+		e   = engi.New(":0")
+	)
+
+	if err := e.RegisterServices(api); err != nil {
+		t.Fatal(err)
+	}
+
+	serveAll(t, e, []served{
+		{"/b/get?id=1&id=2&Name=x", http.StatusOK, `"[1 2] x 10 - - 0"`},
+		// Required parameter with default gets default value if missing, but is validated if passed.
+		{"/b/get?limit=5", http.StatusOK, `"[]  5 - - 0"`},
+		{"/b/get?limit=0", http.StatusBadRequest, "limit"},
+		// Pointers are nil if parameter is missing (printed as "-").
+		{"/b/get?flag=false&since=2024-02-03", http.StatusOK, `"[]  10 false 2024-02-03 0"`},
+		{"/b/get?flag=maybe", http.StatusBadRequest, "flag"},
+		{"/b/get?since=03.02.2024", http.StatusBadRequest, "since"},
+		{"/b/get?day=2024-02-03&day=2024-02-04", http.StatusOK, `"[]  10 - - 2"`},
+		{"/b/get?day=2024-02-03&day=2024-02-04&day=2024-02-05", http.StatusBadRequest, "day"},
+		// Errors of list elements are returned through Bind.
+		{"/b/get?id=1", http.StatusBadRequest, "id"},
+		{"/b/get?id=1&id=1", http.StatusBadRequest, "id"},
+		{"/b/get?id=0&id=1", http.StatusBadRequest, "id[0]"},
+		// All binding errors are responded at once.
+		{"/b/get?id=1&id=0&flag=maybe&limit=0", http.StatusBadRequest, "id[1]"},
+		{"/b/get?id=1&id=0&flag=maybe&limit=0", http.StatusBadRequest, "flag"},
+		{"/b/get?id=1&id=0&flag=maybe&limit=0", http.StatusBadRequest, "limit"},
+	})
+
+	if api.checked != 1 {
+		t.Fatalf("expected validator of field named as parameter to be called once, got %d", api.checked)
+	}
+}
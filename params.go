@@ -0,0 +1,22 @@
+package engi
+
+import (
+	"reflect"
+)
+
+// Params - returns structure bound from request's parameters by 'parameter.Bind[T]'
+// and false if it wasn't bound for route.
+//
+//	params, ok := engi.Params[FilterParams](request)
+func Params[T any](request Request) (T, bool) {
+	var empty T
+
+	value, ok := request.Bound(reflect.TypeOf((*T)(nil)).Elem())
+	if !ok {
+		return empty, false
+	}
+
+	typed, ok := value.(T)
+
+	return typed, ok
+}
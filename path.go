@@ -0,0 +1,279 @@
+package engi
+
+import (
+	"net/http"
+	"net/url"
+	"path"
+	"slices"
+	"strings"
+
+	"github.com/KlyuchnikovV/engi/internal/pathfinder"
+	"github.com/KlyuchnikovV/engi/internal/response"
+)
+
+// PathPolicy - tells engine how to handle requests with non-canonical paths.
+// Canonical path has no empty ('//'), '.' and '..' segments and no trailing slash.
+// Trailing slash is kept for paths served by catch-all routes and their roots (static files, mounted handlers),
+// e.g. directory of static files is served by its index file only with trailing slash.
+type PathPolicy int
+
+const (
+	// PathTolerant - non-canonical paths are served as canonical ones (default).
+	PathTolerant PathPolicy = iota
+	// PathStrict - only canonical paths are matched, others are responded with 404.
+	PathStrict
+	// PathRedirect - non-canonical paths are redirected to canonical ones
+	// with 301 for GET and HEAD requests and 308 for others, so method and body are kept.
+	PathRedirect
+)
+
+// WithPathPolicy - sets how requests with non-canonical paths are handled.
+func WithPathPolicy(policy PathPolicy) Option {
+	return func(engine *Engine) {
+		engine.pathPolicy = policy
+	}
+}
+
+// CaseInsensitivePaths - tells engine to match static parts of paths case-insensitively.
+// Values of path parameters keep their case.
+func CaseInsensitivePaths(engine *Engine) {
+	engine.caseInsensitive = true
+}
+
+type (
+	// dispatcher - routes requests to services by their paths.
+	dispatcher struct {
+		fold   bool
+		routes []dispatch
+	}
+
+	// dispatch - services sharing path ordered by specificity of their conditions.
+	dispatch struct {
+		prefix   string
+		services []*Service
+	}
+)
+
+// newDispatcher - creates dispatcher, longer paths are tried first.
+func newDispatcher(services []*Service, fold bool) *dispatcher {
+	var (
+		result = &dispatcher{fold: fold}
+		byPath = make(map[string]int, len(services))
+	)
+
+	for _, srv := range services {
+		var prefix = strings.TrimSuffix(srv.path, "/")
+
+		i, ok := byPath[prefix]
+		if !ok {
+			i = len(result.routes)
+			byPath[prefix] = i
+			result.routes = append(result.routes, dispatch{prefix: prefix})
+		}
+
+		result.routes[i].services = append(result.routes[i].services, srv)
+	}
+
+	for _, route := range result.routes {
+		slices.SortStableFunc(route.services, func(a, b *Service) int {
+			return b.specificity() - a.specificity()
+		})
+	}
+
+	slices.SortStableFunc(result.routes, func(a, b dispatch) int {
+		return len(b.prefix) - len(a.prefix)
+	})
+
+	return result
+}
+
+// find - returns services serving path and path relative to them.
+func (d *dispatcher) find(escaped string) ([]*Service, string, bool) {
+	for _, route := range d.routes {
+		if len(escaped) < len(route.prefix) {
+			continue
+		}
+
+		var (
+			head = escaped[:len(route.prefix)]
+			rest = escaped[len(route.prefix):]
+		)
+
+		if rest != "" && rest[0] != '/' {
+			continue
+		}
+
+		if head == route.prefix || (d.fold && strings.EqualFold(head, route.prefix)) {
+			return route.services, rest, true
+		}
+	}
+
+	return nil, "", false
+}
+
+// service - returns the first service serving path which conditions request satisfies and path relative to it.
+// Returns nil service if none of services serving path matched.
+func (d *dispatcher) service(r *http.Request, escaped string) ([]*Service, *Service, string, bool) {
+	services, uri, ok := d.find(escaped)
+	if !ok {
+		return nil, nil, "", false
+	}
+
+	var index = slices.IndexFunc(services, func(srv *Service) bool {
+		return srv.matches(r)
+	})
+
+	if index < 0 {
+		return services, nil, uri, true
+	}
+
+	return services, services[index], uri, true
+}
+
+// keepsSlash - checks if path is served by catch-all route or its root, so its trailing slash is meaningful.
+func (d *dispatcher) keepsSlash(r *http.Request, escaped string) bool {
+	_, srv, uri, ok := d.service(r, escaped)
+
+	return ok && srv != nil && srv.keepsSlash(r, uri)
+}
+
+// serve - dispatches request to the first service which conditions it satisfies.
+func (d *dispatcher) serve(w http.ResponseWriter, r *http.Request, escaped string) bool {
+	services, srv, uri, ok := d.service(r, escaped)
+	if !ok {
+		return false
+	}
+
+	if srv == nil {
+		services[0].notFound(w)
+		return true
+	}
+
+	if err := srv.Serve(w, r, uri); err != nil {
+		srv.logger.Error(err.Error())
+	} else {
+		srv.logger.Debug("request handled")
+	}
+
+	return true
+}
+
+// normalize - applies path policy to empty, '.' and '..' segments of request's path,
+// returns escaped canonical path keeping trailing slash and false if request was already responded.
+func (e *Engine) normalize(w http.ResponseWriter, r *http.Request) (string, bool) {
+	var (
+		escaped   = pathfinder.NormalizeEscaped(r.URL.EscapedPath())
+		canonical = canonicalPath(escaped)
+	)
+
+	if canonical == escaped {
+		return canonical, true
+	}
+
+	switch e.pathPolicy {
+	case PathStrict:
+		e.notFound(w)
+		return "", false
+	case PathRedirect:
+		e.redirect(w, r, canonical)
+		return "", false
+	default:
+		return canonical, true
+	}
+}
+
+// trailingSlash - applies path policy to trailing slash of path not served by catch-all route,
+// 'original' is canonical path before api version was cut from it.
+// Returns request and escaped path to serve and false if request was already responded.
+func (e *Engine) trailingSlash(
+	w http.ResponseWriter, r *http.Request, routes *dispatcher, original, escaped string,
+) (*http.Request, string, bool) {
+	if escaped == "/" || !strings.HasSuffix(escaped, "/") || routes.keepsSlash(r, escaped) {
+		return r, escaped, true
+	}
+
+	switch e.pathPolicy {
+	case PathStrict:
+		e.notFound(w)
+		return nil, "", false
+	case PathRedirect:
+		e.redirect(w, r, strings.TrimSuffix(original, "/"))
+		return nil, "", false
+	default:
+		escaped = strings.TrimSuffix(escaped, "/")
+
+		return withPath(r, escaped), escaped, true
+	}
+}
+
+// redirect - redirects request to escaped path keeping query,
+// responds with 301 for GET and HEAD requests and 308 for others.
+func (e *Engine) redirect(w http.ResponseWriter, r *http.Request, escaped string) {
+	var code = http.StatusPermanentRedirect
+	if r.Method == http.MethodGet || r.Method == http.MethodHead {
+		code = http.StatusMovedPermanently
+	}
+
+	var location = escapePath(escaped)
+	if r.URL.RawQuery != "" {
+		location += "?" + r.URL.RawQuery
+	}
+
+	http.Redirect(w, r, location, code)
+}
+
+// canonicalPath - removes empty, '.' and '..' segments from escaped path keeping trailing slash.
+func canonicalPath(escaped string) string {
+	if escaped == "" {
+		return "/"
+	}
+
+	var result = path.Clean("/" + escaped)
+	if result != "/" && strings.HasSuffix(escaped, "/") {
+		result += "/"
+	}
+
+	return result
+}
+
+// escapePath - fully escapes every segment of path returned by NormalizeEscaped,
+// so decoded '?', '#', spaces and non-ASCII bytes can be sent in 'Location' header.
+func escapePath(escaped string) string {
+	var segments = strings.Split(escaped, "/")
+
+	for i, segment := range segments {
+		if unescaped, err := url.PathUnescape(segment); err == nil {
+			segments[i] = url.PathEscape(unescaped)
+		}
+	}
+
+	return strings.Join(segments, "/")
+}
+
+// withPath - returns request having escaped path.
+func withPath(r *http.Request, escaped string) *http.Request {
+	if r.URL.EscapedPath() == escaped {
+		return r
+	}
+
+	unescaped, err := url.PathUnescape(escaped)
+	if err != nil {
+		return r
+	}
+
+	var result = r.Clone(r.Context())
+
+	result.URL.Path, result.URL.RawPath = unescaped, ""
+	if result.URL.EscapedPath() != escaped {
+		result.URL.RawPath = escaped
+	}
+
+	return result
+}
+
+// notFound - responds that path was not found using engine's response object.
+func (e *Engine) notFound(w http.ResponseWriter) {
+	if err := response.New(w, e.responseMarshaler, e.responseObject).NotFound(ErrPathNotFound.Error()); err != nil {
+		e.logger.Error(err.Error())
+	}
+}
@@ -0,0 +1,247 @@
+package engi_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/KlyuchnikovV/engi"
+	"github.com/KlyuchnikovV/engi/parameter/placing"
+)
+
+type pathAPI struct{}
+
+func (pathAPI) Prefix() string { return "s" }
+
+func (pathAPI) Routers() engi.Routes {
+	return engi.Routes{
+		"ui": engi.Static("", fstest.MapFS{
+			"index.html":      {Data: []byte("index")},
+			"docs/index.html": {Data: []byte("docs")},
+		}),
+		"timed": engi.Static("", fstest.MapFS{
+			"file.txt": {Data: []byte("content")},
+		}, engi.StaticMiddlewares(engi.Timeout(time.Second))),
+		"m": engi.Mount("m", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(r.URL.Path)) //nolint:errcheck
+		})),
+		"str/{name}": engi.GET(func(_ context.Context, request engi.Request, response engi.Response) error {
+			return response.OK(request.String("name", placing.InPath))
+		}),
+	}
+}
+
+func newPathEngine(t *testing.T, policy engi.PathPolicy, opts ...engi.Option) *engi.Engine {
+	t.Helper()
+
+	var e = engi.New(":0", append([]engi.Option{engi.WithPrefix("api"), engi.WithPathPolicy(policy)}, opts...)...)
+	if err := e.RegisterServices(pathAPI{}); err != nil {
+		t.Fatal(err)
+	}
+
+	return e
+}
+
+func serve(e *engi.Engine, method, target string) *httptest.ResponseRecorder {
+	var recorder = httptest.NewRecorder()
+
+	e.ServeHTTP(recorder, httptest.NewRequest(method, target, nil))
+
+	return recorder
+}
+
+// served - GET request served by engine and response expected for it.
+type served struct {
+	target string
+	code   int
+	// body - part of response body, not checked if empty.
+	body string
+}
+
+// serveAll - serves GET requests of table checking their responses.
+func serveAll(t *testing.T, e *engi.Engine, table []served) {
+	t.Helper()
+
+	for _, test := range table {
+		var recorder = serve(e, http.MethodGet, test.target)
+
+		if recorder.Code != test.code {
+			t.Fatalf("%s: expected %d, got %d (%s)", test.target, test.code, recorder.Code, recorder.Body)
+		}
+
+		if !strings.Contains(recorder.Body.String(), test.body) {
+			t.Fatalf("%s: expected '%s' in body, got '%s'", test.target, test.body, recorder.Body)
+		}
+	}
+}
+
+func TestStaticDirectoryIndex(t *testing.T) {
+	for _, test := range []struct {
+		name     string
+		policy   engi.PathPolicy
+		target   string
+		code     int
+		location string
+	}{
+		{"tolerant index", engi.PathTolerant, "/api/s/ui/", http.StatusOK, ""},
+		{"tolerant directory", engi.PathTolerant, "/api/s/ui", http.StatusMovedPermanently, "/api/s/ui/"},
+		{"tolerant nested index", engi.PathTolerant, "/api/s/ui/docs/", http.StatusOK, ""},
+		{"tolerant empty segments", engi.PathTolerant, "/api//s/ui/", http.StatusOK, ""},
+		{"redirect index", engi.PathRedirect, "/api/s/ui/", http.StatusOK, ""},
+		{"redirect directory", engi.PathRedirect, "/api/s/ui", http.StatusMovedPermanently, "/api/s/ui/"},
+		{"redirect empty segments", engi.PathRedirect, "/api//s/ui/", http.StatusMovedPermanently, "/api/s/ui/"},
+		{"strict index", engi.PathStrict, "/api/s/ui/", http.StatusOK, ""},
+		{"strict empty segments", engi.PathStrict, "/api//s/ui/", http.StatusNotFound, ""},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			var recorder = serve(newPathEngine(t, test.policy), http.MethodGet, test.target)
+
+			if recorder.Code != test.code {
+				t.Fatalf("expected %d, got %d (%s)", test.code, recorder.Code, recorder.Body)
+			}
+
+			if location := recorder.Header().Get("Location"); location != test.location {
+				t.Fatalf("expected location '%s', got '%s'", test.location, location)
+			}
+		})
+	}
+}
+
+func TestTrailingSlash(t *testing.T) {
+	for _, test := range []struct {
+		name     string
+		policy   engi.PathPolicy
+		code     int
+		location string
+	}{
+		{"tolerant", engi.PathTolerant, http.StatusOK, ""},
+		{"redirect", engi.PathRedirect, http.StatusMovedPermanently, "/api/s/str/a?q=1"},
+		{"strict", engi.PathStrict, http.StatusNotFound, ""},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			var recorder = serve(newPathEngine(t, test.policy), http.MethodGet, "/api/s/str/a/?q=1")
+
+			if recorder.Code != test.code {
+				t.Fatalf("expected %d, got %d (%s)", test.code, recorder.Code, recorder.Body)
+			}
+
+			if location := recorder.Header().Get("Location"); location != test.location {
+				t.Fatalf("expected location '%s', got '%s'", test.location, location)
+			}
+		})
+	}
+}
+
+func TestRedirectLocationIsEscaped(t *testing.T) {
+	for target, location := range map[string]string{
+		"/api//s/str/a%3Fb":     "/api/s/str/a%3Fb",
+		"/api//s/str/a%2Fb":     "/api/s/str/a%2Fb",
+		"/api//s/str/a%20b":     "/api/s/str/a%20b",
+		"/api//s/str/%D0%B0":    "/api/s/str/%D0%B0",
+		"/api//s/str/a%23b?q=1": "/api/s/str/a%23b?q=1",
+	} {
+		var recorder = serve(newPathEngine(t, engi.PathRedirect), http.MethodGet, target)
+
+		if recorder.Code != http.StatusMovedPermanently {
+			t.Fatalf("%s: expected %d, got %d", target, http.StatusMovedPermanently, recorder.Code)
+		}
+
+		if got := recorder.Header().Get("Location"); got != location {
+			t.Fatalf("%s: expected location '%s', got '%s'", target, location, got)
+		}
+	}
+}
+
+func TestCaseInsensitivePaths(t *testing.T) {
+	var e = newPathEngine(t, engi.PathTolerant, engi.CaseInsensitivePaths)
+
+	for _, test := range []struct {
+		target string
+		code   int
+		body   string
+	}{
+		{"/api/s/ui/", http.StatusOK, "index"},
+		{"/API/S/UI/", http.StatusOK, "index"},
+		{"/Api/s/Ui/docs/", http.StatusOK, "docs"},
+		{"/api/s/m/abc", http.StatusOK, "/abc"},
+		{"/API/S/M/abc", http.StatusOK, "/abc"},
+		{"/API/S/M/a%2Fb", http.StatusOK, "/a/b"},
+	} {
+		var recorder = serve(e, http.MethodGet, test.target)
+
+		if recorder.Code != test.code {
+			t.Fatalf("%s: expected %d, got %d (%s)", test.target, test.code, recorder.Code, recorder.Body)
+		}
+
+		if recorder.Body.String() != test.body {
+			t.Fatalf("%s: expected '%s', got '%s'", test.target, test.body, recorder.Body)
+		}
+	}
+}
+
+func TestStaticDirectoryRedirectKeepsVersion(t *testing.T) {
+	var e = newPathEngine(t, engi.PathTolerant, engi.WithVersioning(engi.VersionInPath(), engi.DefaultVersion("1")))
+
+	for target, location := range map[string]string{
+		"/api/v2/s/ui/docs":     "/api/v2/s/ui/docs/",
+		"/api/v2/s/ui/docs?q=1": "/api/v2/s/ui/docs/?q=1",
+		"/api/s/ui/docs":        "/api/s/ui/docs/",
+	} {
+		var recorder = serve(e, http.MethodGet, target)
+
+		if recorder.Code != http.StatusMovedPermanently {
+			t.Fatalf("%s: expected %d, got %d (%s)", target, http.StatusMovedPermanently, recorder.Code, recorder.Body)
+		}
+
+		if got := recorder.Header().Get("Location"); got != location {
+			t.Fatalf("%s: expected location '%s', got '%s'", target, location, got)
+		}
+	}
+}
+
+func TestStaticThroughResponseWrappers(t *testing.T) {
+	var e = newPathEngine(t, engi.PathTolerant)
+
+	for _, test := range []struct {
+		name   string
+		method string
+		target string
+		rng    string
+		code   int
+		body   string
+	}{
+		{"head", http.MethodHead, "/api/s/ui/docs/", "", http.StatusOK, ""},
+		{"timeout", http.MethodGet, "/api/s/timed/file.txt", "", http.StatusOK, "content"},
+		{"head with timeout", http.MethodHead, "/api/s/timed/file.txt", "", http.StatusOK, ""},
+		{"range with timeout", http.MethodGet, "/api/s/timed/file.txt", "bytes=1-3", http.StatusPartialContent, "ont"},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			var (
+				recorder = httptest.NewRecorder()
+				request  = httptest.NewRequest(test.method, test.target, nil)
+			)
+
+			if test.rng != "" {
+				request.Header.Set("Range", test.rng)
+			}
+
+			e.ServeHTTP(recorder, request)
+
+			if recorder.Code != test.code {
+				t.Fatalf("expected %d, got %d (%s)", test.code, recorder.Code, recorder.Body)
+			}
+
+			if recorder.Body.String() != test.body {
+				t.Fatalf("expected '%s', got '%s'", test.body, recorder.Body)
+			}
+
+			if recorder.Header().Get("Content-Length") == "" {
+				t.Fatal("expected Content-Length header")
+			}
+		})
+	}
+}
@@ -0,0 +1,70 @@
+package engi
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"runtime/debug"
+
+	"github.com/KlyuchnikovV/engi/internal/request"
+	"github.com/KlyuchnikovV/engi/internal/response"
+)
+
+const panicMessage = "internal server error"
+
+type (
+	// PanicHook - called after panic in request pipeline was recovered, e.g. to report it to error tracker.
+	PanicHook func(ctx context.Context, request Request, recovered interface{}, stack []byte)
+
+	// panicked - panic recovered in another goroutine together with its stack.
+	panicked struct {
+		value interface{}
+		stack []byte
+	}
+)
+
+// WithPanicHook - sets hook called on every recovered panic.
+func WithPanicHook(hook PanicHook) Option {
+	return func(engine *Engine) {
+		engine.panicHook = hook
+	}
+}
+
+// recoverPanic - recovers panic of route, logs it and responds with 500.
+// Must be deferred directly.
+func (srv *Service) recoverPanic(
+	ctx context.Context,
+	pattern string,
+	request *request.Request,
+	response *response.Response,
+	err *error,
+) {
+	var recovered = recover()
+	if recovered == nil {
+		return
+	}
+
+	if recovered == http.ErrAbortHandler {
+		// Aborting handler is handled by server.
+		panic(recovered)
+	}
+
+	var stack = debug.Stack()
+	if p, ok := recovered.(*panicked); ok {
+		recovered, stack = p.value, p.stack
+	}
+
+	srv.logger.Error("panic recovered",
+		slog.String("method", request.GetRequest().Method),
+		slog.String("route", pattern),
+		slog.String("panic", fmt.Sprint(recovered)),
+		slog.String("stack", string(stack)),
+	)
+
+	if srv.panicHook != nil {
+		srv.panicHook(ctx, request, recovered, stack)
+	}
+
+	*err = response.InternalServerError(panicMessage)
+}
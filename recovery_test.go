@@ -0,0 +1,116 @@
+package engi_test
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/KlyuchnikovV/engi"
+	"github.com/KlyuchnikovV/engi/internal/request"
+	"github.com/KlyuchnikovV/engi/parameter/placing"
+	"github.com/KlyuchnikovV/engi/parameter/query"
+)
+
+type panicAPI struct{}
+
+func (panicAPI) Prefix() string { return "p" }
+
+func (panicAPI) Routers() engi.Routes {
+	var panicking = func(context.Context, engi.Request, engi.Response) error {
+		panic("secret")
+	}
+
+	return engi.Routes{
+		"route":   engi.GET(panicking),
+		"timeout": engi.GET(panicking, engi.Timeout(time.Second)),
+		"conversion": engi.GET(func(_ context.Context, request engi.Request, response engi.Response) error {
+			// Declared string parameter can't be converted to integer.
+			return response.OK(request.Integer("id", placing.InQuery))
+		}, query.String("id")),
+		"validator": engi.GET(func(_ context.Context, _ engi.Request, response engi.Response) error {
+			return response.OK("unreachable")
+		}, query.String("id", request.Validator(func(*request.Parameter) error {
+			panic("secret")
+		}))),
+		"abort": engi.GET(func(context.Context, engi.Request, engi.Response) error {
+			panic(http.ErrAbortHandler)
+		}),
+	}
+}
+
+func TestPanicRecovery(t *testing.T) {
+	var (
+		logs      bytes.Buffer
+		recovered []interface{}
+		e         = engi.New(":0",
+			engi.WithLogger(slog.NewTextHandler(&logs, nil)),
+			engi.WithPanicHook(func(_ context.Context, _ engi.Request, value interface{}, stack []byte) {
+				if len(stack) == 0 {
+					t.Error("expected stack trace")
+				}
+
+				recovered = append(recovered, value)
+			}),
+		)
+	)
+
+	if err := e.RegisterServices(panicAPI{}); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, test := range []struct {
+		name    string
+		target  string
+		pattern string
+	}{
+		{"route", "/p/route", "GET /p/route"},
+		{"route with timeout", "/p/timeout", "GET /p/timeout"},
+		{"conversion", "/p/conversion?id=abc", "GET /p/conversion"},
+		{"validator", "/p/validator?id=abc", "GET /p/validator"},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			logs.Reset()
+			recovered = nil
+
+			var recorder = serve(e, http.MethodGet, test.target)
+
+			if recorder.Code != http.StatusInternalServerError {
+				t.Fatalf("expected %d, got %d (%s)", http.StatusInternalServerError, recorder.Code, recorder.Body)
+			}
+
+			if body := recorder.Body.String(); !strings.Contains(body, "internal server error") || strings.Contains(body, "secret") {
+				t.Fatalf("expected generic message, got '%s'", body)
+			}
+
+			if len(recovered) != 1 {
+				t.Fatalf("expected hook called once, got %d", len(recovered))
+			}
+
+			for _, attr := range []string{"method=GET", "route=\"" + test.pattern + "\"", "service=p", "stack="} {
+				if !strings.Contains(logs.String(), attr) {
+					t.Fatalf("expected '%s' in log, got '%s'", attr, logs.String())
+				}
+			}
+		})
+	}
+}
+
+func TestAbortHandlerIsNotRecovered(t *testing.T) {
+	var e = engi.New(":0", engi.WithLogger(slog.NewTextHandler(&bytes.Buffer{}, nil)))
+
+	if err := e.RegisterServices(panicAPI{}); err != nil {
+		t.Fatal(err)
+	}
+
+	defer func() {
+		if recovered := recover(); recovered != http.ErrAbortHandler {
+			t.Fatalf("expected '%v' panic, got '%v'", http.ErrAbortHandler, recovered)
+		}
+	}()
+
+	serve(e, http.MethodGet, "/p/abort")
+}
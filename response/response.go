@@ -28,10 +28,11 @@ func (obj *AsIs) MarshalJSON() ([]byte, error) {
 }
 
 type AsObject struct {
-	XMLName     xml.Name    `json:"-"                xml:"response"`
-	Code        int         `json:"-"                xml:"-"`
-	Result      interface{} `json:"result,omitempty" xml:"result,omitempty"`
-	ErrorString string      `json:"error,omitempty"  xml:"error,omitempty"`
+	XMLName     xml.Name    `json:"-"                 xml:"response"`
+	Code        int         `json:"-"                 xml:"-"`
+	Result      interface{} `json:"result,omitempty"  xml:"result,omitempty"`
+	ErrorString string      `json:"error,omitempty"   xml:"error,omitempty"`
+	Details     interface{} `json:"details,omitempty" xml:"details,omitempty"`
 }
 
 // SetPayload - sets response payload into object.
@@ -44,6 +45,11 @@ func (a *AsObject) SetError(err error) {
 	a.ErrorString = err.Error()
 }
 
+// SetDetails - sets error details into object.
+func (a *AsObject) SetDetails(details interface{}) {
+	a.Details = details
+}
+
 func (a AsObject) Error() string {
 	return a.ErrorString
 }
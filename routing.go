@@ -0,0 +1,57 @@
+package engi
+
+import (
+	"net/http"
+
+	"github.com/KlyuchnikovV/engi/internal/middlewares"
+	"github.com/KlyuchnikovV/engi/internal/pathfinder"
+	"github.com/KlyuchnikovV/engi/internal/response"
+)
+
+// Host - routes requests to route, group or service only if request's host matches one of patterns (port is ignored).
+// '*' matches any label and '{name}' matches any label capturing it,
+// captured value can be obtained with 'request.GetParameter(name, placing.InHost)':
+//
+//	engi.Host("{tenant}.example.com", "*.example.org")
+func Host(patterns ...string) Register {
+	return func(middlewares *middlewares.Middlewares) {
+		middlewares.AddConditions(pathfinder.Host(patterns...))
+	}
+}
+
+// Header - routes requests to route, group or service only if header has one of values
+// or, if no values passed, if header is set.
+//
+//	engi.Header("X-Tenant", "acme")
+func Header(name string, values ...string) Register {
+	return func(middlewares *middlewares.Middlewares) {
+		middlewares.AddConditions(pathfinder.Header(name, values...))
+	}
+}
+
+// matches - checks that request satisfies conditions of service.
+func (srv *Service) matches(r *http.Request) bool {
+	for _, condition := range srv.conditions {
+		if _, ok := condition.Match(r); !ok {
+			return false
+		}
+	}
+
+	return true
+}
+
+// specificity - returns specificity of service's conditions, service without conditions has the lowest one.
+func (srv *Service) specificity() int {
+	if len(srv.conditions) == 0 {
+		return -1
+	}
+
+	return pathfinder.Specificity(srv.conditions)
+}
+
+// notFound - responds that no route matches request.
+func (srv *Service) notFound(w http.ResponseWriter) {
+	if err := response.New(w, srv.marshaler, srv.responser).NotFound(ErrPathNotFound.Error()); err != nil {
+		srv.logger.Error(err.Error())
+	}
+}
@@ -0,0 +1,102 @@
+package engi_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/KlyuchnikovV/engi"
+	"github.com/KlyuchnikovV/engi/parameter/placing"
+)
+
+// hostAPI - service with the same prefix served for different hosts.
+type hostAPI struct {
+	name  string
+	hosts []string
+}
+
+func (api hostAPI) Prefix() string { return "r" }
+
+func (api hostAPI) Middlewares() []engi.Register {
+	if len(api.hosts) == 0 {
+		return nil
+	}
+
+	return []engi.Register{engi.Host(api.hosts...)}
+}
+
+func (api hostAPI) Routers() engi.Routes {
+	var reply = func(name string) engi.Route {
+		return func(_ context.Context, request engi.Request, response engi.Response) error {
+			return response.OK(strings.TrimSpace(name + " " + request.GetParameter("tenant", placing.InHost)))
+		}
+	}
+
+	return engi.Routes{
+		"who": engi.GET(reply(api.name)),
+		"reports": engi.Group(engi.Routes{
+			"daily": engi.GET(reply(api.name + " acme reports")),
+		}, engi.Header("X-Tenant", "acme")),
+		"/reports/": engi.Group(engi.Routes{
+			"daily": engi.GET(reply(api.name+" reports"), engi.Header("X-Tenant")),
+		}),
+	}
+}
+
+func TestHostAndHeaderRouting(t *testing.T) {
+	var e = engi.New(":0")
+
+	if err := e.RegisterServices(
+		hostAPI{name: "default"},
+		hostAPI{name: "tenant", hosts: []string{"{tenant}.example.com"}},
+		hostAPI{name: "admin", hosts: []string{"admin.example.com", "*.admin.example.com"}},
+	); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, test := range []struct {
+		name   string
+		host   string
+		target string
+		header string
+		code   int
+		body   string
+	}{
+		{"without host", "localhost", "/r/who", "", http.StatusOK, `"default"`},
+		{"captured subdomain", "acme.example.com", "/r/who", "", http.StatusOK, `"tenant acme"`},
+		{"port is ignored", "acme.example.com:8080", "/r/who", "", http.StatusOK, `"tenant acme"`},
+		{"host case is ignored", "ACME.Example.com", "/r/who", "", http.StatusOK, `"tenant acme"`},
+		{"exact host over capture", "admin.example.com", "/r/who", "", http.StatusOK, `"admin"`},
+		{"wildcard", "eu.admin.example.com", "/r/who", "", http.StatusOK, `"admin"`},
+		{"not matched host", "a.b.example.com", "/r/who", "", http.StatusOK, `"default"`},
+		{"header value", "localhost", "/r/reports/daily", "acme", http.StatusOK, `"default acme reports"`},
+		{"header set", "localhost", "/r/reports/daily", "other", http.StatusOK, `"default reports"`},
+		{"header missing", "localhost", "/r/reports/daily", "", http.StatusNotFound, ""},
+		{"host and header", "acme.example.com", "/r/reports/daily", "acme", http.StatusOK, `"tenant acme reports acme"`},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			var (
+				recorder = httptest.NewRecorder()
+				request  = httptest.NewRequest(http.MethodGet, test.target, nil)
+			)
+
+			request.Host = test.host
+
+			if test.header != "" {
+				request.Header.Set("X-Tenant", test.header)
+			}
+
+			e.ServeHTTP(recorder, request)
+
+			if recorder.Code != test.code {
+				t.Fatalf("expected %d, got %d (%s)", test.code, recorder.Code, recorder.Body)
+			}
+
+			if test.body != "" && recorder.Body.String() != test.body {
+				t.Fatalf("expected '%s', got '%s'", test.body, recorder.Body)
+			}
+		})
+	}
+}
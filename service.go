@@ -2,9 +2,11 @@ package engi
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
 	"net/http"
+	"slices"
 	"strings"
 
 	"github.com/KlyuchnikovV/engi/internal/middlewares"
@@ -14,9 +16,18 @@ import (
 	"github.com/KlyuchnikovV/engi/internal/types"
 )
 
+const (
+	allowHeader             = "Allow"
+	corsRequestMethodHeader = "Access-Control-Request-Method"
+	corsAllowMethodsHeader  = "Access-Control-Allow-Methods"
+)
+
 var (
-	ErrMethodNotAppliable = fmt.Errorf("method not appliable for path")
-	ErrPathNotFound       = fmt.Errorf("path not found for method")
+	ErrMethodNotAppliable       = fmt.Errorf("method not appliable for path")
+	ErrPathNotFound             = fmt.Errorf("path not found for method")
+	ErrRouteAlreadyRegistered   = fmt.Errorf("route already registered")
+	ErrServiceAlreadyRegistered = fmt.Errorf("service with same prefix already registered")
+	ErrServiceNotFound          = fmt.Errorf("service not registered")
 )
 
 type (
@@ -35,10 +46,35 @@ type (
 	// Service - provides basic service methods.
 	Service struct {
 		handlers map[string]*pathfinder.PathFinder
+		// preflights - CORS checks of routes by method, matched the same way as handlers.
+		preflights map[string]*pathfinder.PathFinder
 
 		marshaler types.Marshaler
 		responser types.Responser
 
+		inFlight     *inFlight
+		panicHook    PanicHook
+		errorHandler ErrorHandler
+
+		// engineMiddlewares - middlewares applied to every route before service's ones.
+		engineMiddlewares []Register
+		// groupMiddlewares - middlewares of group routes are registered in, applied after service's ones.
+		groupMiddlewares []Register
+
+		// names - patterns of named routes relative to service's path.
+		names map[string]string
+		// links - names of routes referenced by service's routes and patterns of referencing routes.
+		links map[string]string
+
+		// conditions - conditions of service's middlewares request has to satisfy to be routed to service.
+		conditions []pathfinder.Condition
+
+		// caseInsensitive - static parts of paths are matched case-insensitively.
+		caseInsensitive bool
+
+		// components - service api and its nested services in registration order.
+		components []component
+
 		logger *slog.Logger
 
 		api  ServiceAPI
@@ -50,23 +86,68 @@ type (
 )
 
 func NewService(engine *Engine, api ServiceAPI, path string) *Service {
-	slog.New(engine.logger.Handler().WithAttrs([]slog.Attr{
+	var logger = slog.New(engine.logger.Handler().WithAttrs([]slog.Attr{
 		slog.String("service", api.Prefix()),
 	}))
 
-	return &Service{
-		handlers: make(map[string]*pathfinder.PathFinder),
+	var srv = &Service{
+		handlers:   make(map[string]*pathfinder.PathFinder),
+		preflights: make(map[string]*pathfinder.PathFinder),
+		names:      make(map[string]string),
+		links:      make(map[string]string),
 
 		marshaler: engine.responseMarshaler,
 		responser: engine.responseObject,
 
+		inFlight:     engine.inFlight,
+		panicHook:    engine.panicHook,
+		errorHandler: engine.errorHandler,
+
+		engineMiddlewares: engine.middlewares,
+		caseInsensitive:   engine.caseInsensitive,
+
 		api:  api,
 		path: path,
+		components: []component{{
+			name:   strings.Trim(api.Prefix(), "/"),
+			api:    api,
+			logger: logger,
+		}},
+
+		logger: logger,
+	}
+
+	var own = middlewares.New()
+	for _, middleware := range srv.Middlewares() {
+		middleware(own)
+	}
 
-		logger: slog.New(engine.logger.Handler().WithAttrs([]slog.Attr{
-			slog.String("service", api.Prefix()),
-		})),
+	srv.conditions = own.Conditions()
+
+	// Without versioning requests have no version, so service's versions are ignored.
+	if condition := newVersionCondition(api); condition != nil && engine.versioning != nil {
+		srv.conditions = append(srv.conditions, condition)
 	}
+
+	return srv
+}
+
+// key - identifies service by prefix and conditions, so services with same prefix can serve different hosts.
+func (srv *Service) key() string {
+	var keys = make([]string, 0, len(srv.conditions))
+
+	for _, condition := range srv.conditions {
+		keys = append(keys, condition.Key())
+	}
+
+	slices.Sort(keys)
+
+	return strings.Join(append([]string{srv.Prefix()}, keys...), " ")
+}
+
+// Prefix - returns service prefix without surrounding slashes.
+func (srv *Service) Prefix() string {
+	return strings.Trim(srv.api.Prefix(), "/")
 }
 
 func (srv *Service) Middlewares() []Register {
@@ -83,25 +164,67 @@ func (srv *Service) add(
 	route Route,
 	options ...Register,
 ) error {
-	if _, ok := srv.handlers[method]; !ok {
-		srv.handlers[method] = pathfinder.NewPathFinder()
+	var middlewares = middlewares.New()
+	for _, middleware := range srv.engineMiddlewares {
+		middleware(middlewares)
 	}
 
-	var middlewares = middlewares.New()
 	for _, middleware := range srv.Middlewares() {
 		middleware(middlewares)
 	}
 
+	for _, middleware := range srv.groupMiddlewares {
+		middleware(middlewares)
+	}
+
 	for _, middleware := range options {
 		middleware(middlewares)
 	}
 
-	srv.handlers[method].Add(path, srv.handleEndpoint(
+	var pattern = fmt.Sprintf("%s %s%s", method, srv.path, path)
+
+	if name := middlewares.Name(); name != "" {
+		if _, ok := srv.names[name]; ok {
+			return fmt.Errorf("%w: '%s'", ErrRouteNameAlreadyRegistered, name)
+		}
+
+		srv.names[name] = path
+	}
+
+	for _, name := range middlewares.Links() {
+		srv.links[name] = pattern
+	}
+
+	if err := srv.finder(srv.handlers, method).Add(path, srv.handleEndpoint(
+		pattern,
 		route,
 		middlewares,
-	))
+	), middlewares.Conditions()...); err != nil {
+		if errors.Is(err, pathfinder.ErrAlreadyRegistered) {
+			err = ErrRouteAlreadyRegistered
+		}
 
-	return nil
+		return fmt.Errorf("%w: '%s'", err, pattern)
+	}
+
+	// Route is already added with same path and conditions, so it can't fail.
+	return srv.finder(srv.preflights, method).Add(path, preflight(middlewares), middlewares.Conditions()...)
+}
+
+// finder - returns path finder of method, creating it if needed.
+func (srv *Service) finder(finders map[string]*pathfinder.PathFinder, method string) *pathfinder.PathFinder {
+	if finder, ok := finders[method]; ok {
+		return finder
+	}
+
+	var options = make([]pathfinder.Option, 0, 1)
+	if srv.caseInsensitive {
+		options = append(options, pathfinder.IgnoreCase)
+	}
+
+	finders[method] = pathfinder.NewPathFinder(options...)
+
+	return finders[method]
 }
 
 func (srv *Service) Serve(
@@ -112,38 +235,202 @@ func (srv *Service) Serve(
 		slog.String("path", r.URL.Path),
 	)
 
+	uri = strings.Trim(uri, "/")
+
 	var (
-		request  = request.New(r)
-		response = response.New(w, srv.marshaler, srv.responser)
+		request = request.New(r)
+		handler = srv.find(r.Method, request, uri)
 	)
 
-	if _, ok := srv.handlers[r.Method]; !ok {
-		return response.NotFound(ErrMethodNotAppliable.Error())
+	// HEAD is served by GET handler if service has no own one.
+	if handler == nil && r.Method == http.MethodHead {
+		if handler = srv.find(http.MethodGet, request, uri); handler != nil {
+			w = response.NewHeadWriter(w)
+		}
+	}
+
+	var response = response.New(w, srv.marshaler, srv.responser)
+
+	if handler != nil {
+		return handler(r.Context(), request, response)
 	}
 
-	var handler = srv.handlers[r.Method].Handle(request, strings.Trim(uri, "/"))
-	if handler == nil {
+	var allowed = srv.allowedMethods(r, uri)
+	if len(allowed) == 0 {
 		return response.NotFound(ErrPathNotFound.Error())
 	}
 
-	return handler(r.Context(), request, response)
+	w.Header().Set(allowHeader, strings.Join(allowed, ", "))
+
+	if r.Method == http.MethodOptions {
+		return srv.options(request, response, uri, allowed)
+	}
+
+	return response.MethodNotAllowed(ErrMethodNotAppliable.Error())
+}
+
+// find - returns handler registered for method and path.
+func (srv *Service) find(method string, request *request.Request, uri string) pathfinder.Handler {
+	finder, ok := srv.handlers[method]
+	if !ok {
+		return nil
+	}
+
+	return finder.Handle(request, uri)
+}
+
+// findPreflight - returns CORS check of route requested method is routed to,
+// if method is not set or has no route, route of the first allowed method is used.
+func (srv *Service) findPreflight(request *request.Request, uri string, allowed []string) pathfinder.Handler {
+	var methods = append([]string{request.GetRequest().Header.Get(corsRequestMethodHeader)}, allowed...)
+
+	for _, method := range methods {
+		// HEAD is served by GET route if service has no own one.
+		for _, candidate := range []string{method, http.MethodGet} {
+			if finder, ok := srv.preflights[candidate]; ok {
+				if handler := finder.Handle(request, uri); handler != nil {
+					return handler
+				}
+			}
+
+			if method != http.MethodHead {
+				break
+			}
+		}
+	}
+
+	return nil
+}
+
+// keepsSlash - checks if uri is served by catch-all route or its root for request's method.
+func (srv *Service) keepsSlash(r *http.Request, uri string) bool {
+	uri = strings.Trim(uri, "/")
+
+	if finder, ok := srv.handlers[r.Method]; ok && finder.Match(r, uri) {
+		return finder.KeepsSlash(r, uri)
+	}
+
+	if finder, ok := srv.handlers[http.MethodGet]; ok && r.Method == http.MethodHead {
+		return finder.KeepsSlash(r, uri)
+	}
+
+	return false
+}
+
+// allowedMethods - returns sorted methods path can be requested with, including automatic HEAD and OPTIONS.
+func (srv *Service) allowedMethods(r *http.Request, uri string) []string {
+	var allowed = make([]string, 0, len(srv.handlers))
+
+	for method, finder := range srv.handlers {
+		if finder.Match(r, uri) {
+			allowed = append(allowed, method)
+		}
+	}
+
+	if len(allowed) == 0 {
+		return nil
+	}
+
+	for _, method := range []string{http.MethodHead, http.MethodOptions} {
+		if method == http.MethodHead && !slices.Contains(allowed, http.MethodGet) {
+			continue
+		}
+
+		if !slices.Contains(allowed, method) {
+			allowed = append(allowed, method)
+		}
+	}
+
+	slices.Sort(allowed)
+
+	return allowed
+}
+
+// options - responds to OPTIONS request for path without own OPTIONS handler.
+// CORS preflight is checked with CORS settings of route requested method is routed to,
+// so settings of route's group and route itself are applied.
+func (srv *Service) options(request *request.Request, response *response.Response, uri string, allowed []string) error {
+	var check = srv.findPreflight(request, uri, allowed)
+	if check == nil {
+		check = preflight(srv.ownMiddlewares())
+	}
+
+	if err := check(request.GetRequest().Context(), request, response); err != nil {
+		return err
+	}
+
+	if request.GetRequest().Header.Get(corsRequestMethodHeader) != "" {
+		response.ResponseWriter().Header().Set(corsAllowMethodsHeader, strings.Join(allowed, ", "))
+	}
+
+	return response.NoContent()
+}
+
+// ownMiddlewares - returns engine's and service's middlewares.
+func (srv *Service) ownMiddlewares() *middlewares.Middlewares {
+	var middlewares = middlewares.New()
+	for _, middleware := range srv.engineMiddlewares {
+		middleware(middlewares)
+	}
+
+	for _, middleware := range srv.Middlewares() {
+		middleware(middlewares)
+	}
+
+	return middlewares
+}
+
+// preflight - returns CORS check of route with middlewares.
+func preflight(middlewares *middlewares.Middlewares) pathfinder.Handler {
+	return func(_ context.Context, request *request.Request, response *response.Response) error {
+		if err := middlewares.HandleCORS(request, response.ResponseWriter()); err != nil && err.Code != http.StatusOK {
+			return response.Error(err.Code, "%s", err.ErrorString)
+		}
+
+		return nil
+	}
 }
 
 func (srv *Service) handleEndpoint(
+	pattern string,
 	route Route,
 	middlewares *middlewares.Middlewares,
 ) pathfinder.Handler {
-	return func(ctx context.Context, request *request.Request, response *response.Response) error {
+	return func(ctx context.Context, request *request.Request, response *response.Response) (err error) {
+		var done = srv.inFlight.begin(pattern)
+		defer done()
+
+		defer srv.recoverPanic(ctx, pattern, request, response, &err)
+
 		if err := middlewares.Handle(request, response.ResponseWriter()); err != nil {
-			return response.Error(err.Code, err.ErrorString)
+			return response.ErrorWithDetails(err.Code, err.ErrorString, err.Details)
 		}
 
-		if err := route(ctx, request, response); err != nil {
-			if err := response.InternalServerError(err.Error()); err != nil {
-				return err
-			}
+		if timeout := middlewares.Timeout(); timeout != nil {
+			return srv.callWithTimeout(ctx, timeout, route, request, response)
 		}
 
+		return srv.call(ctx, route, request, response)
+	}
+}
+
+// call - calls route responding with error it returned using error handler.
+func (srv *Service) call(
+	ctx context.Context,
+	route Route,
+	request *request.Request,
+	response *response.Response,
+) error {
+	var err = route(ctx, request, response)
+	if err == nil {
 		return nil
 	}
+
+	if code, _, _ := resolveError(err); code >= http.StatusInternalServerError {
+		srv.logger.Error("route failed", slog.String("error", err.Error()))
+	} else {
+		srv.logger.Debug("route failed", slog.String("error", err.Error()))
+	}
+
+	return srv.errorHandler(ctx, request, response, err)
 }
@@ -0,0 +1,85 @@
+package engi_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/KlyuchnikovV/engi"
+)
+
+type methodsAPI struct{}
+
+func (methodsAPI) Prefix() string { return "n" }
+
+func (methodsAPI) Routers() engi.Routes {
+	var ok = func(_ context.Context, _ engi.Request, response engi.Response) error {
+		response.ResponseWriter().Header().Set("Route", "true")
+		return response.OK("body")
+	}
+
+	return engi.Routes{
+		"notes":      engi.GET(ok),
+		"/notes/":    engi.POST(ok),
+		"notes/{id}": engi.DELETE(ok),
+		"own":        engi.GET(ok),
+		"/own/": engi.OPTIONS(func(_ context.Context, _ engi.Request, response engi.Response) error {
+			response.ResponseWriter().Header().Set("Allow", "custom")
+			return response.OK("options")
+		}),
+		"head": engi.GET(ok),
+		"/head/": engi.HEAD(func(_ context.Context, _ engi.Request, response engi.Response) error {
+			response.ResponseWriter().Header().Set("Own-Head", "true")
+			return response.NoContent()
+		}),
+	}
+}
+
+func TestMethods(t *testing.T) {
+	var e = engi.New(":0")
+	if err := e.RegisterServices(methodsAPI{}); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, test := range []struct {
+		name   string
+		method string
+		target string
+		code   int
+		allow  string
+		body   string
+		header string
+	}{
+		{"registered method", http.MethodGet, "/n/notes", http.StatusOK, "", "\"body\"", "Route"},
+		{"not allowed method", http.MethodPut, "/n/notes", http.StatusMethodNotAllowed, "GET, HEAD, OPTIONS, POST", "", ""},
+		{"not allowed without GET", http.MethodGet, "/n/notes/5", http.StatusMethodNotAllowed, "DELETE, OPTIONS", "", ""},
+		{"unknown path", http.MethodPut, "/n/unknown", http.StatusNotFound, "", "", ""},
+		{"head from GET", http.MethodHead, "/n/notes", http.StatusOK, "", "", "Route"},
+		{"head without GET", http.MethodHead, "/n/notes/5", http.StatusMethodNotAllowed, "DELETE, OPTIONS", "", ""},
+		{"own head", http.MethodHead, "/n/head", http.StatusNoContent, "", "", "Own-Head"},
+		{"automatic options", http.MethodOptions, "/n/notes", http.StatusNoContent, "GET, HEAD, OPTIONS, POST", "", ""},
+		{"automatic options with parameter", http.MethodOptions, "/n/notes/5", http.StatusNoContent, "DELETE, OPTIONS", "", ""},
+		{"own options", http.MethodOptions, "/n/own", http.StatusOK, "custom", "\"options\"", ""},
+		{"options of unknown path", http.MethodOptions, "/n/unknown", http.StatusNotFound, "", "", ""},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			var recorder = serve(e, test.method, test.target)
+
+			if recorder.Code != test.code {
+				t.Fatalf("expected %d, got %d (%s)", test.code, recorder.Code, recorder.Body)
+			}
+
+			if allow := recorder.Header().Get("Allow"); allow != test.allow {
+				t.Fatalf("expected Allow '%s', got '%s'", test.allow, allow)
+			}
+
+			if test.code < http.StatusBadRequest && recorder.Body.String() != test.body {
+				t.Fatalf("expected body '%s', got '%s'", test.body, recorder.Body)
+			}
+
+			if test.header != "" && recorder.Header().Get(test.header) != "true" {
+				t.Fatalf("expected '%s' header set by route", test.header)
+			}
+		})
+	}
+}
@@ -0,0 +1,56 @@
+package engi
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+)
+
+var ErrShutdownTimeout = fmt.Errorf("shutdown deadline reached")
+
+// inFlight - tracks handlers that are currently serving requests.
+type inFlight struct {
+	mutex  sync.Mutex
+	routes map[string]int
+}
+
+func newInFlight() *inFlight {
+	return &inFlight{
+		routes: make(map[string]int),
+	}
+}
+
+// begin - marks route as running and returns function marking it as finished.
+func (f *inFlight) begin(route string) func() {
+	f.mutex.Lock()
+	f.routes[route]++
+	f.mutex.Unlock()
+
+	return func() {
+		f.mutex.Lock()
+		defer f.mutex.Unlock()
+
+		if f.routes[route]--; f.routes[route] <= 0 {
+			delete(f.routes, route)
+		}
+	}
+}
+
+// running - returns sorted list of routes with requests in progress.
+func (f *inFlight) running() []string {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	var routes = make([]string, 0, len(f.routes))
+	for route, count := range f.routes {
+		if count > 1 {
+			route = fmt.Sprintf("%s (x%d)", route, count)
+		}
+
+		routes = append(routes, route)
+	}
+
+	sort.Strings(routes)
+
+	return routes
+}
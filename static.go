@@ -0,0 +1,95 @@
+package engi
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"net/http"
+	"strings"
+
+	"github.com/KlyuchnikovV/engi/internal/static"
+)
+
+type (
+	// StaticOption - configures static files route.
+	StaticOption func(*staticConfig)
+
+	staticConfig struct {
+		index       string
+		spa         bool
+		middlewares []Register
+	}
+)
+
+// SPA - serves index file for paths not found under route, so single page app can handle them itself.
+func SPA() StaticOption {
+	return func(config *staticConfig) {
+		config.spa = true
+	}
+}
+
+// IndexFile - sets name of file served for directories (default is 'index.html').
+func IndexFile(name string) StaticOption {
+	return func(config *staticConfig) {
+		config.index = name
+	}
+}
+
+// StaticMiddlewares - sets middlewares of static files route.
+func StaticMiddlewares(middlewares ...Register) StaticOption {
+	return func(config *staticConfig) {
+		config.middlewares = append(config.middlewares, middlewares...)
+	}
+}
+
+// Static - serves files of 'root' directory of file system (e.g. embed.FS) under route's path.
+// Directories are served by index files, files get MIME type by extension, ETag and Last-Modified headers,
+// conditional and Range requests are supported. If client accepts gzip, precompressed '.gz' sibling is served instead of file.
+//
+//	//go:embed assets
+//	var assets embed.FS
+//
+//	"ui": engi.Static("assets", assets, engi.SPA()),
+func Static(root string, fsys fs.FS, options ...StaticOption) RouteByPath {
+	return func(srv *Service, path string) error {
+		var config staticConfig
+		for _, option := range options {
+			option(&config)
+		}
+
+		var files = fsys
+
+		if root = strings.Trim(root, "/"); root != "" {
+			sub, err := fs.Sub(fsys, root)
+			if err != nil {
+				return err
+			}
+
+			files = sub
+		}
+
+		var (
+			server = static.New(files, config.index, config.spa)
+			prefix = strings.TrimSuffix(srv.path+path, "/")
+		)
+
+		return srv.addTree([]string{http.MethodGet}, path, func(
+			_ context.Context, request Request, response Response,
+		) error {
+			var (
+				r       = request.GetRequest()
+				name, _ = cutPrefix(r.URL.Path, prefix, srv.caseInsensitive)
+			)
+
+			if err := server.Serve(response.ResponseWriter(), r, name, escapePath(requestedPath(r))); err != nil {
+				if errors.Is(err, fs.ErrNotExist) {
+					return response.NotFound("file not found")
+				}
+
+				return err
+			}
+
+			return nil
+		}, config.middlewares...)
+	}
+}
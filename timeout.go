@@ -0,0 +1,174 @@
+package engi
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"runtime/debug"
+	"strconv"
+	"time"
+
+	"github.com/KlyuchnikovV/engi/internal/middlewares"
+	"github.com/KlyuchnikovV/engi/internal/request"
+	"github.com/KlyuchnikovV/engi/internal/response"
+)
+
+const timeoutMessage = "request timed out"
+
+// TimeoutOption - configures route's timeout.
+type TimeoutOption func(*middlewares.Timeout)
+
+// Timeout - sets deadline on context passed to route.
+// When deadline exceeds, client gets 503 error (see TimeoutCode) through service's responser
+// and everything written by route is dropped. Zero 'timeout' means no deadline unless client sets it
+// with header (see TimeoutFromHeader). Response flushed by route (e.g. with http.ResponseController)
+// is sent to client immediately and can't be replaced with error anymore.
+func Timeout(timeout time.Duration, opts ...TimeoutOption) Register {
+	var settings = middlewares.Timeout{
+		Duration: timeout,
+		Code:     http.StatusServiceUnavailable,
+	}
+
+	for _, opt := range opts {
+		opt(&settings)
+	}
+
+	return func(middlewares *middlewares.Middlewares) {
+		middlewares.SetTimeout(settings)
+	}
+}
+
+// TimeoutCode - sets http code responded when route's deadline exceeds (e.g. http.StatusGatewayTimeout).
+func TimeoutCode(code int) TimeoutOption {
+	return func(timeout *middlewares.Timeout) {
+		timeout.Code = code
+	}
+}
+
+// TimeoutFromHeader - allows client to set route's timeout with header (e.g. 'Request-Timeout'),
+// value is either number of seconds or duration like '1m30s' and is capped at 'max'.
+func TimeoutFromHeader(header string, max time.Duration) TimeoutOption {
+	return func(timeout *middlewares.Timeout) {
+		timeout.Header = header
+		timeout.Max = max
+	}
+}
+
+// routeTimeout - returns route deadline for request.
+func routeTimeout(settings *middlewares.Timeout, r *http.Request) time.Duration {
+	if settings.Header == "" {
+		return settings.Duration
+	}
+
+	var value = r.Header.Get(settings.Header)
+	if value == "" {
+		return settings.Duration
+	}
+
+	timeout, err := time.ParseDuration(value)
+	if err != nil {
+		seconds, err := strconv.ParseFloat(value, request.BitSize)
+		if err != nil || seconds <= 0 {
+			return settings.Duration
+		}
+
+		timeout = time.Duration(seconds * float64(time.Second))
+	}
+
+	if timeout <= 0 {
+		return settings.Duration
+	}
+
+	if settings.Max > 0 && timeout > settings.Max {
+		return settings.Max
+	}
+
+	return timeout
+}
+
+// callWithTimeout - calls route with deadline, responding with error if route didn't finish in time.
+func (srv *Service) callWithTimeout(
+	ctx context.Context,
+	settings *middlewares.Timeout,
+	route Route,
+	request *request.Request,
+	resp *response.Response,
+) error {
+	var timeout = routeTimeout(settings, request.GetRequest())
+	if timeout <= 0 {
+		// Route has no default deadline and client didn't set one.
+		return srv.call(ctx, route, request, resp)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	var (
+		writer   = response.NewTimeoutWriter(resp.ResponseWriter())
+		buffered = response.New(writer, srv.marshaler, srv.responser)
+		done     = make(chan error, 1)
+		panics   = make(chan interface{}, 1)
+		// routeErr - error returned by route itself, read only after route is done.
+		routeErr error
+		timed    = func(ctx context.Context, request Request, response Response) error {
+			routeErr = route(ctx, request, response)
+			return routeErr
+		}
+	)
+
+	go func() {
+		defer func() {
+			if p := recover(); p != nil {
+				panics <- &panicked{value: p, stack: debug.Stack()}
+			}
+		}()
+
+		done <- srv.call(ctx, timed, request, buffered)
+	}()
+
+	select {
+	case p := <-panics:
+		writer.TimedOut()
+		panic(p)
+	case err := <-done:
+		// Route noticed deadline before engine did.
+		if errors.Is(routeErr, context.DeadlineExceeded) && errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return timedOut(writer, resp, settings)
+		}
+
+		if err == nil {
+			return writer.Commit()
+		}
+
+		// Error handler failed to respond, partially written response is sent as is.
+		if writer.Written() {
+			return errors.Join(err, writer.Commit())
+		}
+
+		writer.TimedOut()
+
+		return errors.Join(err,
+			resp.InternalServerError(http.StatusText(http.StatusInternalServerError)),
+		)
+	case <-ctx.Done():
+		writer.TimedOut()
+
+		if !errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			// Client has gone, nobody to respond to.
+			return ctx.Err()
+		}
+
+		return timedOut(writer, resp, settings)
+	}
+}
+
+// timedOut - responds with timeout error, unless route has already flushed part of its response.
+func timedOut(writer *response.TimeoutWriter, resp *response.Response, settings *middlewares.Timeout) error {
+	writer.TimedOut()
+
+	if writer.Committed() {
+		return http.ErrHandlerTimeout
+	}
+
+	return resp.Error(settings.Code, timeoutMessage)
+}
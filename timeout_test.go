@@ -0,0 +1,133 @@
+package engi_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/KlyuchnikovV/engi"
+)
+
+type timeoutAPI struct{}
+
+func (timeoutAPI) Prefix() string { return "t" }
+
+func (timeoutAPI) Routers() engi.Routes {
+	var sleep = func(ctx context.Context, _ engi.Request, response engi.Response) error {
+		select {
+		case <-time.After(50 * time.Millisecond):
+			return response.OK("done")
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+
+	return engi.Routes{
+		"header": engi.GET(sleep, engi.Timeout(0, engi.TimeoutFromHeader("Request-Timeout", time.Second))),
+		"deadline": engi.GET(func(ctx context.Context, _ engi.Request, _ engi.Response) error {
+			<-ctx.Done()
+			return ctx.Err()
+		}, engi.Timeout(time.Nanosecond)),
+		"failing": engi.GET(func(context.Context, engi.Request, engi.Response) error {
+			return errors.New("failed")
+		}, engi.Timeout(time.Second)),
+		"stream": engi.HandlerFunc(http.MethodGet, func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte("part")) //nolint:errcheck
+
+			if err := http.NewResponseController(w).Flush(); err != nil {
+				w.WriteHeader(http.StatusInternalServerError)
+				return
+			}
+
+			<-r.Context().Done()
+		}, engi.Timeout(10*time.Millisecond)),
+	}
+}
+
+func TestTimeout(t *testing.T) {
+	var e = engi.New(":0", engi.WithErrorHandler(
+		func(_ context.Context, _ engi.Request, _ engi.Response, err error) error {
+			// Error handler failing to respond.
+			return err
+		},
+	))
+
+	if err := e.RegisterServices(timeoutAPI{}); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, test := range []struct {
+		name    string
+		target  string
+		timeout string
+		code    int
+	}{
+		{"zero timeout without header", "/t/header", "", http.StatusOK},
+		{"timeout from header", "/t/header", "10ms", http.StatusServiceUnavailable},
+		{"failed error handler", "/t/failing", "", http.StatusInternalServerError},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			var (
+				recorder = httptest.NewRecorder()
+				request  = httptest.NewRequest(http.MethodGet, test.target, nil)
+			)
+
+			if test.timeout != "" {
+				request.Header.Set("Request-Timeout", test.timeout)
+			}
+
+			e.ServeHTTP(recorder, request)
+
+			if recorder.Code != test.code {
+				t.Fatalf("expected %d, got %d (%s)", test.code, recorder.Code, recorder.Body)
+			}
+		})
+	}
+}
+
+func TestFlushedResponseIsNotReplacedOnTimeout(t *testing.T) {
+	var e = engi.New(":0")
+
+	if err := e.RegisterServices(timeoutAPI{}); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, method := range []string{http.MethodGet, http.MethodHead} {
+		t.Run(method, func(t *testing.T) {
+			var recorder = serve(e, method, "/t/stream")
+
+			if recorder.Code != http.StatusOK || !recorder.Flushed {
+				t.Fatalf("expected flushed %d, got %d (flushed: %t)", http.StatusOK, recorder.Code, recorder.Flushed)
+			}
+
+			var body = "part"
+			if method == http.MethodHead {
+				body = ""
+			}
+
+			if recorder.Body.String() != body {
+				t.Fatalf("expected body '%s', got '%s'", body, recorder.Body)
+			}
+		})
+	}
+}
+
+func TestTimeoutWhenRouteReturnsDeadlineError(t *testing.T) {
+	var e = engi.New(":0")
+
+	if err := e.RegisterServices(timeoutAPI{}); err != nil {
+		t.Fatal(err)
+	}
+
+	// Route returns deadline error at the same time engine notices deadline, so either of them can be handled first.
+	for i := 0; i < 50; i++ {
+		var recorder = serve(e, http.MethodGet, "/t/deadline")
+
+		if recorder.Code != http.StatusServiceUnavailable {
+			t.Fatalf("expected %d, got %d (%s)", http.StatusServiceUnavailable, recorder.Code, recorder.Body)
+		}
+	}
+}
@@ -0,0 +1,96 @@
+package engi
+
+import (
+	"crypto/tls"
+	"log/slog"
+
+	"github.com/KlyuchnikovV/engi/internal/certs"
+)
+
+type tlsOption func(engine *Engine, config *tls.Config) error
+
+// WithTLS - serves api over TLS using certificate and key from files.
+// Files are watched and certificate is reloaded when they change on disk.
+func WithTLS(certFile, keyFile string) Option {
+	return func(engine *Engine) {
+		engine.tlsOptions = append(engine.tlsOptions, func(engine *Engine, config *tls.Config) error {
+			reloader, err := certs.NewReloader(certFile, keyFile, func(err error) {
+				engine.logger.Error("reloading certificate failed", slog.String("error", err.Error()))
+			})
+			if err != nil {
+				return err
+			}
+
+			config.GetCertificate = reloader.GetCertificate
+
+			return nil
+		})
+	}
+}
+
+// WithTLSConfig - serves api over TLS using custom configuration.
+// Other TLS options are applied on top of it.
+func WithTLSConfig(config *tls.Config) Option {
+	return func(engine *Engine) {
+		engine.server.TLSConfig = config.Clone()
+	}
+}
+
+// WithSelfSignedTLS - serves api over TLS using generated in-memory self-signed certificate for hosts.
+// If no hosts provided certificate is generated for localhost. Intended for development only.
+func WithSelfSignedTLS(hosts ...string) Option {
+	return func(engine *Engine) {
+		engine.tlsOptions = append(engine.tlsOptions, func(engine *Engine, config *tls.Config) error {
+			cert, err := certs.SelfSigned(hosts...)
+			if err != nil {
+				return err
+			}
+
+			config.Certificates = append(config.Certificates, cert)
+
+			engine.logger.Warn("using self-signed certificate", slog.Any("hosts", hosts))
+
+			return nil
+		})
+	}
+}
+
+// WithClientCA - requires clients to present certificate signed by one of CAs from PEM bundle file.
+func WithClientCA(caFile string) Option {
+	return func(engine *Engine) {
+		engine.tlsOptions = append(engine.tlsOptions, func(_ *Engine, config *tls.Config) error {
+			pool, err := certs.CertPool(caFile)
+			if err != nil {
+				return err
+			}
+
+			config.ClientCAs = pool
+			config.ClientAuth = tls.RequireAndVerifyClientCert
+
+			return nil
+		})
+	}
+}
+
+// setupTLS - builds server TLS configuration from options, does nothing if no TLS options were provided.
+func (e *Engine) setupTLS() error {
+	if len(e.tlsOptions) == 0 {
+		return nil
+	}
+
+	if e.server.TLSConfig == nil {
+		e.server.TLSConfig = &tls.Config{
+			MinVersion: tls.VersionTLS12,
+		}
+	}
+
+	for _, option := range e.tlsOptions {
+		if err := option(e, e.server.TLSConfig); err != nil {
+			return err
+		}
+	}
+
+	e.tlsOptions = nil
+
+	return nil
+}
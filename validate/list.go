@@ -0,0 +1,115 @@
+package validate
+
+import (
+	"fmt"
+	"net/http"
+	"reflect"
+	"time"
+
+	"github.com/KlyuchnikovV/engi/internal/request"
+	"github.com/KlyuchnikovV/engi/response"
+)
+
+// Each - checks every element of list parameter with all of passed checks.
+//
+//	query.Integers("id", ",", validate.Each(validate.Greater(0)))
+func Each(opts ...request.Option) request.Validator {
+	return func(p *request.Parameter) error {
+		list, err := listOf(p)
+		if err != nil {
+			return err
+		}
+
+		for i := 0; i < list.Len(); i++ {
+			var element = request.Parameter{
+				Name:        fmt.Sprintf("%s[%d]", p.Name, i),
+				Description: p.Description,
+				Parsed:      list.Index(i).Interface(),
+			}
+
+			for _, option := range opts {
+				if err := option.Validate(&element); err != nil {
+					return err
+				}
+			}
+		}
+
+		return nil
+	}
+}
+
+// MinItems - checks if list parameter has at least 'count' elements.
+func MinItems(count int) request.Validator {
+	return func(p *request.Parameter) error {
+		list, err := listOf(p)
+		if err != nil {
+			return err
+		}
+
+		if list.Len() >= count {
+			return nil
+		}
+
+		return response.AsError(http.StatusBadRequest,
+			"'%s' should have at least %d items (got: %d)", p.Name, count, list.Len(),
+		)
+	}
+}
+
+// MaxItems - checks if list parameter has at most 'count' elements.
+func MaxItems(count int) request.Validator {
+	return func(p *request.Parameter) error {
+		list, err := listOf(p)
+		if err != nil {
+			return err
+		}
+
+		if list.Len() <= count {
+			return nil
+		}
+
+		return response.AsError(http.StatusBadRequest,
+			"'%s' should have at most %d items (got: %d)", p.Name, count, list.Len(),
+		)
+	}
+}
+
+// Unique - checks if list parameter has no repeated elements.
+// NOTE: times are compared as instants regardless of their location.
+var Unique request.Validator = func(p *request.Parameter) error {
+	list, err := listOf(p)
+	if err != nil {
+		return err
+	}
+
+	var seen = make(map[interface{}]struct{}, list.Len())
+
+	for i := 0; i < list.Len(); i++ {
+		var element = list.Index(i).Interface()
+		if typed, ok := element.(time.Time); ok {
+			element = typed.UnixNano()
+		}
+
+		if _, ok := seen[element]; ok {
+			return response.AsError(http.StatusBadRequest,
+				"'%s' should have unique items (repeated: %v)", p.Name, list.Index(i).Interface(),
+			)
+		}
+
+		seen[element] = struct{}{}
+	}
+
+	return nil
+}
+
+// listOf - returns list parameter's value or error if parameter is not a list.
+func listOf(p *request.Parameter) (reflect.Value, error) {
+	var value = reflect.ValueOf(p.Parsed)
+	if value.Kind() != reflect.Slice {
+		return value, response.AsError(http.StatusInternalServerError,
+			"'%s' is not a list parameter", p.Name,
+		)
+	}
+
+	return value, nil
+}
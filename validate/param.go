@@ -11,7 +11,7 @@ import (
 
 // NotEmpty - checks if parameter is not empty by it's type.
 // NOTE: boolean parameter will be ignored.
-func NotEmpty(p *request.Parameter) error {
+var NotEmpty request.Validator = func(p *request.Parameter) error {
 	var isNotEmpty func() bool
 
 	switch typed := p.Parsed.(type) {
@@ -44,7 +44,7 @@ func NotEmpty(p *request.Parameter) error {
 //   - for 'int' and 'float' parameters - simple values comparison;
 //   - for 'string' - comparing with it's length;
 //   - for 'time' - comparing with time.Unix() value in seconds;
-func Greater(than float64) request.Option {
+func Greater(than float64) request.Validator {
 	return func(p *request.Parameter) error {
 		var greater func() bool
 
@@ -74,7 +74,7 @@ func Greater(than float64) request.Option {
 //   - for 'int' and 'float' parameters - simple values comparison;
 //   - for 'string' - comparing with it's length;
 //   - for 'time' - comparing with time.Unix() value in seconds;
-func Less(than float64) request.Option {
+func Less(than float64) request.Validator {
 	return func(p *request.Parameter) error {
 		var greater func() bool
 
@@ -100,7 +100,7 @@ func Less(than float64) request.Option {
 }
 
 // OR - combines several parameter checks and passes if one of them successful.
-func OR(opts ...request.Option) request.Option {
+func OR(opts ...request.Option) request.Validator {
 	return func(p *request.Parameter) error {
 		var (
 			passed bool
@@ -108,7 +108,7 @@ func OR(opts ...request.Option) request.Option {
 		)
 
 		for _, option := range opts {
-			if err := option(p); err != nil {
+			if err := option.Validate(p); err != nil {
 				errs = append(errs, err.Error())
 				continue
 			}
@@ -129,12 +129,12 @@ func OR(opts ...request.Option) request.Option {
 }
 
 // AND - combines several parameter checks and failing if one of them failed.
-func AND(opts ...request.Option) request.Option {
+func AND(opts ...request.Option) request.Validator {
 	return func(p *request.Parameter) error {
 		var err error
 
 		for _, option := range opts {
-			if err = option(p); err != nil {
+			if err = option.Validate(p); err != nil {
 				break
 			}
 		}
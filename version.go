@@ -0,0 +1,295 @@
+package engi
+
+import (
+	"context"
+	"fmt"
+	"mime"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/KlyuchnikovV/engi/internal/pathfinder"
+)
+
+const (
+	acceptHeader     = "Accept"
+	varyHeader       = "Vary"
+	versionParameter = "version"
+)
+
+type (
+	// VersionsAPI - optional interface of ServiceAPI declaring api versions service serves ('1', 'v2'...).
+	// Services with same prefix can serve different versions, service without versions serves any of them.
+	// Versions are ignored unless engine is created with WithVersioning.
+	VersionsAPI interface {
+		Versions() []string
+	}
+
+	// VersioningOption - configures how requested api version is selected.
+	VersioningOption func(*versioning)
+
+	versioning struct {
+		path     bool
+		accept   bool
+		header   string
+		fallback string
+	}
+
+	versionKey struct{}
+
+	// requestedPathKey - context key of escaped path requested by client before version segment was cut from it.
+	requestedPathKey struct{}
+
+	// versionCondition - routes request to service only if selected version is one of service's versions.
+	versionCondition struct {
+		versions []string
+	}
+)
+
+// WithVersioning - enables api versioning, version is selected by sources in order:
+// path segment following api's prefix, 'version' parameter of 'Accept' media type, header.
+// Requests without version get default one.
+//
+//	engi.WithVersioning(engi.VersionInPath(), engi.VersionInHeader("API-Version"), engi.DefaultVersion("1"))
+func WithVersioning(options ...VersioningOption) Option {
+	return func(engine *Engine) {
+		engine.versioning = new(versioning)
+
+		for _, option := range options {
+			option(engine.versioning)
+		}
+	}
+}
+
+// VersionInPath - selects version by path segment following api's prefix ('/api/v2/notes/...').
+// Segment is removed from path before routing.
+func VersionInPath() VersioningOption {
+	return func(versioning *versioning) {
+		versioning.path = true
+	}
+}
+
+// VersionInAccept - selects version by parameter of 'Accept' media type ('application/vnd.x+json; version=2').
+func VersionInAccept() VersioningOption {
+	return func(versioning *versioning) {
+		versioning.accept = true
+	}
+}
+
+// VersionInHeader - selects version by header.
+func VersionInHeader(name string) VersioningOption {
+	return func(versioning *versioning) {
+		versioning.header = http.CanonicalHeaderKey(name)
+	}
+}
+
+// DefaultVersion - sets version of requests not specifying it.
+func DefaultVersion(version string) VersioningOption {
+	return func(versioning *versioning) {
+		versioning.fallback = normalizeVersion(version)
+	}
+}
+
+// Version - returns api version selected for request without 'v' prefix ('2')
+// or empty string if versioning is disabled.
+// Middlewares can obtain it with 'engi.Version(request.GetRequest().Context())'.
+func Version(ctx context.Context) string {
+	version, _ := ctx.Value(versionKey{}).(string)
+
+	return version
+}
+
+// requestedPath - returns escaped path requested by client including version segment cut by engine,
+// so redirects keep requested version.
+func requestedPath(r *http.Request) string {
+	if escaped, ok := r.Context().Value(requestedPathKey{}).(string); ok {
+		return escaped
+	}
+
+	return r.URL.EscapedPath()
+}
+
+// withVersion - returns request with selected version in context and escaped path with version's segment removed.
+func (e *Engine) withVersion(w http.ResponseWriter, r *http.Request, escaped string) (*http.Request, string) {
+	var (
+		config  = e.versioning
+		version string
+	)
+
+	if config.path {
+		if path, selected, ok := e.cutVersion(escaped); ok {
+			r = withPath(r, path).WithContext(context.WithValue(r.Context(), requestedPathKey{}, escaped))
+			escaped, version = path, selected
+		}
+	}
+
+	if config.accept {
+		w.Header().Add(varyHeader, acceptHeader)
+
+		if version == "" {
+			version = acceptVersion(r.Header.Values(acceptHeader))
+		}
+	}
+
+	if config.header != "" {
+		w.Header().Add(varyHeader, config.header)
+
+		if version == "" {
+			version = normalizeVersion(r.Header.Get(config.header))
+		}
+	}
+
+	if version == "" {
+		version = config.fallback
+	}
+
+	return r.WithContext(context.WithValue(r.Context(), versionKey{}, version)), escaped
+}
+
+// cutVersion - removes version segment following api's prefix from path.
+// Segment is treated as version if it looks like 'v2' or 'v2.1'.
+func (e *Engine) cutVersion(path string) (string, string, bool) {
+	var prefix = e.apiPrefix + "/"
+	if len(path) < len(prefix) || !(path[:len(prefix)] == prefix || (e.caseInsensitive && strings.EqualFold(path[:len(prefix)], prefix))) {
+		return path, "", false
+	}
+
+	var rest = path[len(prefix):]
+
+	segment, rest, _ := strings.Cut(rest, "/")
+	if !isVersion(segment) {
+		return path, "", false
+	}
+
+	return fmt.Sprintf("%s/%s", e.apiPrefix, rest), normalizeVersion(segment), true
+}
+
+// versionedPath - returns path of service with version segment following api's prefix
+// if version is selected by path and service declares versions.
+func (e *Engine) versionedPath(srv *Service) string {
+	if e.versioning == nil || !e.versioning.path {
+		return srv.path
+	}
+
+	var index = slices.IndexFunc(srv.conditions, func(condition pathfinder.Condition) bool {
+		_, ok := condition.(versionCondition)
+		return ok
+	})
+
+	if index < 0 {
+		return srv.path
+	}
+
+	var (
+		versions = srv.conditions[index].(versionCondition).versions
+		version  = e.versioning.fallback
+	)
+
+	if len(versions) == 0 {
+		return srv.path
+	}
+
+	if !slices.Contains(versions, version) {
+		version = slices.MaxFunc(versions, compareVersions)
+	}
+
+	return fmt.Sprintf("%s/v%s%s", e.apiPrefix, version, strings.TrimPrefix(srv.path, e.apiPrefix))
+}
+
+// compareVersions - compares versions by their numeric parts, so '10' is greater than '2'.
+func compareVersions(a, b string) int {
+	var (
+		left  = strings.Split(a, ".")
+		right = strings.Split(b, ".")
+	)
+
+	for i := 0; i < len(left) && i < len(right); i++ {
+		x, errX := strconv.Atoi(left[i])
+		y, errY := strconv.Atoi(right[i])
+
+		if errX != nil || errY != nil {
+			if result := strings.Compare(left[i], right[i]); result != 0 {
+				return result
+			}
+
+			continue
+		}
+
+		if x != y {
+			return x - y
+		}
+	}
+
+	return len(left) - len(right)
+}
+
+// isVersion - checks that path segment looks like version ('v2', 'v2.1').
+func isVersion(segment string) bool {
+	var number, ok = strings.CutPrefix(strings.ToLower(segment), "v")
+	if !ok || number == "" {
+		return false
+	}
+
+	for _, r := range number {
+		if (r < '0' || r > '9') && r != '.' {
+			return false
+		}
+	}
+
+	return true
+}
+
+// acceptVersion - returns 'version' parameter of the first 'Accept' media type having it.
+func acceptVersion(headers []string) string {
+	for _, header := range headers {
+		for _, mediaType := range strings.Split(header, ",") {
+			_, params, err := mime.ParseMediaType(mediaType)
+			if err != nil {
+				continue
+			}
+
+			if version, ok := params[versionParameter]; ok {
+				return normalizeVersion(version)
+			}
+		}
+	}
+
+	return ""
+}
+
+// normalizeVersion - returns version without 'v' prefix.
+func normalizeVersion(version string) string {
+	version = strings.ToLower(strings.TrimSpace(version))
+
+	return strings.TrimPrefix(version, "v")
+}
+
+// newVersionCondition - returns condition of service's versions or nil if service doesn't declare them.
+func newVersionCondition(api ServiceAPI) pathfinder.Condition {
+	versionsAPI, ok := api.(VersionsAPI)
+	if !ok {
+		return nil
+	}
+
+	var versions = make([]string, 0)
+	for _, version := range versionsAPI.Versions() {
+		versions = append(versions, normalizeVersion(version))
+	}
+
+	slices.Sort(versions)
+
+	return versionCondition{versions: versions}
+}
+
+func (condition versionCondition) Key() string {
+	return fmt.Sprintf("version(%s)", strings.Join(condition.versions, ","))
+}
+
+func (condition versionCondition) Match(r *http.Request) (map[string]string, bool) {
+	return nil, slices.Contains(condition.versions, Version(r.Context()))
+}
+
+func (condition versionCondition) Specificity() int {
+	return 1
+}